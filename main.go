@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	supportscolor "github.com/jwalton/go-supportscolor"
 	cli "github.com/urfave/cli/v2"
@@ -16,10 +19,81 @@ var (
 	version        string
 	date           string
 	kubeConfigFile = &cli.StringFlag{
-		Name:    "kubeconfig",
-		Value:   "", // overwritten by init function
-		Usage:   "Path to kube config file.",
-		EnvVars: []string{"KUBECONFIG"},
+		Name:  "kubeconfig",
+		Usage: "Path to kube config file, the files listed in $KUBECONFIG or ~/.kube/config are merged if empty.",
+	}
+	kubeContexts = &cli.StringSliceFlag{
+		Name:  "context",
+		Usage: "Comma separated list of kube config contexts to evaluate, the current context is used if empty. Several contexts are evaluated in parallel.",
+	}
+	kubeCluster = &cli.StringFlag{
+		Name:  "cluster",
+		Usage: "Name of the kube config cluster to use.",
+	}
+	kubeUser = &cli.StringFlag{
+		Name:  "user",
+		Usage: "Name of the kube config user to use.",
+	}
+	namespace = &cli.StringSliceFlag{
+		Name:    "namespace",
+		Aliases: []string{"n"},
+		Usage:   "Comma separated list of namespaces to limit the namespaced checks to. If empty, all namespaces are evaluated or the namespace of the context if pods may not be listed in all namespaces.",
+	}
+	allContexts = &cli.BoolFlag{
+		Name:  "all-contexts",
+		Usage: "Evaluate all contexts of the kube config in parallel.",
+	}
+	configFile = &cli.StringFlag{
+		Name:  "config",
+		Value: "", // overwritten by init function
+		Usage: "Path to the k8status config file.",
+	}
+	output = &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Value:   k8status.OutputText,
+		Usage:   fmt.Sprintf("Output format, one of %v.", k8status.OutputFormats),
+	}
+	exitCodeMode = &cli.StringFlag{
+		Name:  "exit-code-mode",
+		Value: k8status.ExitCodeModeSeverity,
+		Usage: fmt.Sprintf("How the exit codes of the checks are combined, one of %v. See the exit-codes command.", k8status.ExitCodeModes),
+	}
+	timeout = &cli.DurationFlag{
+		Name:  "timeout",
+		Value: time.Minute,
+		Usage: "Maximum time to evaluate the checks, checks still running afterwards are reported as timed out. Per check timeouts can be set in the config file.",
+	}
+	only = &cli.StringSliceFlag{
+		Name:  "only",
+		Usage: "Comma separated list of checks to evaluate, all checks are evaluated if empty.",
+	}
+	skip = &cli.StringSliceFlag{
+		Name:  "skip",
+		Usage: "Comma separated list of checks to skip.",
+	}
+	printClusterRole = &cli.BoolFlag{
+		Name:  "cluster-role",
+		Usage: "Print a ClusterRole and namespaced Roles granting all permissions needed by the checks instead of verifying them.",
+	}
+	serviceAccount = &cli.StringFlag{
+		Name:  "service-account",
+		Usage: "Bind the printed roles to a service account, formatted as namespace:name.",
+	}
+	listen = &cli.StringFlag{
+		Name:  "listen",
+		Value: ":8080",
+		Usage: "Address to serve http requests on.",
+	}
+	interval = &cli.DurationFlag{
+		Name:  "interval",
+		Value: time.Minute,
+		Usage: "Maximum time between two evaluations of the checks, changes in the cluster trigger an evaluation earlier.",
+	}
+	watchInterval = &cli.DurationFlag{
+		Name:  "interval",
+		Value: 10 * time.Second,
+		Usage: "Maximum time between two evaluations of the checks, changes in the cluster trigger an evaluation earlier.",
 	}
 	app = &cli.App{
 		Name:   "K8status",
@@ -27,12 +101,106 @@ var (
 		Action: run,
 		Flags: []cli.Flag{
 			kubeConfigFile,
+			kubeContexts,
+			allContexts,
+			kubeCluster,
+			kubeUser,
+			namespace,
+			configFile,
+			output,
+			exitCodeMode,
+			timeout,
+			only,
+			skip,
 		},
 		Commands: []*cli.Command{
 			{
 				Name:   "run",
 				Usage:  "Show the health overview.",
 				Action: run,
+				Flags: []cli.Flag{
+					kubeConfigFile,
+					kubeContexts,
+					allContexts,
+					kubeCluster,
+					kubeUser,
+					namespace,
+					configFile,
+					output,
+					exitCodeMode,
+					timeout,
+					only,
+					skip,
+				},
+			},
+			{
+				Name:   "watch",
+				Usage:  "Show the health overview and refresh it continuously.",
+				Action: watch,
+				Flags: []cli.Flag{
+					kubeConfigFile,
+					kubeContexts,
+					kubeCluster,
+					kubeUser,
+					namespace,
+					configFile,
+					watchInterval,
+					timeout,
+					only,
+					skip,
+				},
+			},
+			{
+				Name:   "serve",
+				Usage:  "Evaluate the checks periodically and expose the results as prometheus metrics and health endpoints.",
+				Action: serve,
+				Flags: []cli.Flag{
+					kubeConfigFile,
+					kubeContexts,
+					kubeCluster,
+					kubeUser,
+					namespace,
+					configFile,
+					listen,
+					interval,
+					exitCodeMode,
+					timeout,
+					only,
+					skip,
+				},
+			},
+			{
+				Name:   "preflight",
+				Usage:  "Verify that the current identity has all permissions needed by the checks.",
+				Action: preflight,
+				Flags: []cli.Flag{
+					kubeConfigFile,
+					kubeContexts,
+					kubeCluster,
+					kubeUser,
+					namespace,
+					configFile,
+					printClusterRole,
+					serviceAccount,
+					only,
+					skip,
+				},
+			},
+			{
+				Name:  "checks",
+				Usage: "Inspect the available checks.",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "List the available checks and their exit codes.",
+						Action: listChecks,
+					},
+				},
+			},
+			{
+				Name:   "exit-codes",
+				Usage:  "Explain the exit codes.",
+				Action: printExitCodes,
 			},
 			{
 				Name:   "version",
@@ -49,7 +217,7 @@ func init() {
 		log.Fatalf("look up home directory: %v", err)
 	}
 
-	kubeConfigFile.Value = filepath.Join(dir, ".kube", "config")
+	configFile.Value = filepath.Join(dir, ".config", "k8status", "config.yaml")
 }
 
 func main() {
@@ -59,17 +227,163 @@ func main() {
 	}
 }
 
+func options(c *cli.Context) (k8status.Options, error) {
+	config, err := k8status.LoadConfig(c.String(configFile.Name), c.IsSet(configFile.Name))
+	if err != nil {
+		return k8status.Options{}, err
+	}
+
+	if c.IsSet(timeout.Name) {
+		config.Timeout.Duration = c.Duration(timeout.Name)
+		if config.Timeout.Duration <= 0 {
+			return k8status.Options{}, fmt.Errorf("timeout must be positive, got %v", config.Timeout.Duration)
+		}
+	}
+
+	format := c.String(output.Name)
+	if !c.IsSet(output.Name) && config.Output != "" {
+		format = config.Output
+	}
+
+	return k8status.Options{
+		Colored:      supportscolor.Stdout().SupportsColor,
+		Output:       format,
+		ExitCodeMode: c.String(exitCodeMode.Name),
+		Only:         c.StringSlice(only.Name),
+		Skip:         c.StringSlice(skip.Name),
+		Config:       config,
+	}, nil
+}
+
+// clientOptions selects the cluster like kubectl, context overwrites the current context if set.
+func clientOptions(c *cli.Context, context string) k8status.ClientOptions {
+	return k8status.ClientOptions{
+		Kubeconfig: c.String(kubeConfigFile.Name),
+		Context:    context,
+		Cluster:    c.String(kubeCluster.Name),
+		User:       c.String(kubeUser.Name),
+		Namespaces: c.StringSlice(namespace.Name),
+	}
+}
+
+// client connects to a single cluster.
+func client(c *cli.Context) (*k8status.KubernetesClient, error) {
+	contexts := c.StringSlice(kubeContexts.Name)
+	if len(contexts) > 1 {
+		return nil, fmt.Errorf("%s supports a single context only, got %v", c.Command.Name, contexts)
+	}
+
+	context := ""
+	if len(contexts) == 1 {
+		context = contexts[0]
+	}
+
+	return k8status.NewKubernetesClient(clientOptions(c, context))
+}
+
 func run(c *cli.Context) error {
-	kubeConfigFile := c.String(kubeConfigFile.Name)
-	ctx := c.Context
-	colored := supportscolor.Stdout().SupportsColor
+	options, err := options(c)
+	if err != nil {
+		return err
+	}
 
-	k8sClient, err := k8status.NewKubernetesClient(kubeConfigFile)
+	// interrupted checks are reported, the results of the other checks are printed anyway
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	contexts := c.StringSlice(kubeContexts.Name)
+	if c.Bool(allContexts.Name) {
+		contexts, err = k8status.KubeContexts(clientOptions(c, ""))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(contexts) > 1 || c.Bool(allContexts.Name) {
+		clusters := []k8status.Cluster{}
+		for _, context := range contexts {
+			k8sClient, err := k8status.NewKubernetesClient(clientOptions(c, context))
+			if err != nil {
+				return fmt.Errorf("context %s: %v", context, err)
+			}
+
+			clusters = append(clusters, k8status.Cluster{Name: context, Client: k8sClient})
+		}
+
+		return k8status.RunClusters(ctx, clusters, options)
+	}
+
+	k8sClient, err := client(c)
+	if err != nil {
+		return err
+	}
+
+	return k8status.Run(ctx, k8sClient, options)
+}
+
+func watch(c *cli.Context) error {
+	interval := c.Duration(watchInterval.Name)
+
+	options, err := options(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	k8sClient, err := client(c)
 	if err != nil {
 		return err
 	}
 
-	return k8status.Run(ctx, k8sClient, colored)
+	return k8status.Watch(ctx, k8sClient, options, interval)
+}
+
+func serve(c *cli.Context) error {
+	listen := c.String(listen.Name)
+	interval := c.Duration(interval.Name)
+
+	options, err := options(c)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	k8sClient, err := client(c)
+	if err != nil {
+		return err
+	}
+
+	return k8status.Serve(ctx, k8sClient, options, listen, interval)
+}
+
+func preflight(c *cli.Context) error {
+	options, err := options(c)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool(printClusterRole.Name) {
+		return k8status.PrintClusterRole(os.Stdout, options, c.String(serviceAccount.Name))
+	}
+
+	k8sClient, err := client(c)
+	if err != nil {
+		return err
+	}
+
+	return k8status.Preflight(c.Context, k8sClient, options)
+}
+
+func listChecks(c *cli.Context) error {
+	return k8status.PrintChecks(os.Stdout, supportscolor.Stdout().SupportsColor)
+}
+
+func printExitCodes(c *cli.Context) error {
+	return k8status.PrintExitCodes(os.Stdout, supportscolor.Stdout().SupportsColor)
 }
 
 func printVersion(c *cli.Context) error {
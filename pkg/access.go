@@ -0,0 +1,229 @@
+package k8status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resource is an API resource read by a check.
+type resource struct {
+	group      string
+	name       string
+	namespaced bool
+}
+
+var (
+	resourceNodes                  = resource{name: "nodes"}
+	resourceNamespaces             = resource{name: "namespaces"}
+	resourcePersistentVolumes      = resource{name: "persistentvolumes"}
+	resourcePersistentVolumeClaims = resource{name: "persistentvolumeclaims", namespaced: true}
+	resourcePods                   = resource{name: "pods", namespaced: true}
+	resourceDeployments            = resource{group: "apps", name: "deployments", namespaced: true}
+	resourceStatefulSets           = resource{group: "apps", name: "statefulsets", namespaced: true}
+	resourceDaemonSets             = resource{group: "apps", name: "daemonsets", namespaced: true}
+	resourceReplicaSets            = resource{group: "apps", name: "replicasets", namespaced: true}
+	resourceJobs                   = resource{group: "batch", name: "jobs", namespaced: true}
+	resourceCronJobs               = resource{group: "batch", name: "cronjobs", namespaced: true}
+	resourceSecrets                = resource{name: "secrets", namespaced: true}
+	resourceEvents                 = resource{name: "events", namespaced: true}
+)
+
+// permission is an API access needed by a check.
+type permission struct {
+	verb        string
+	resource    resource
+	subresource string
+	// namespace is empty for cluster-scoped resources and access to all namespaces.
+	namespace string
+	// name restricts the permission to a single object, it is empty for all objects.
+	name string
+}
+
+func (r resource) String() string {
+	if r.group == "" {
+		return r.name
+	}
+
+	return r.name + "." + r.group
+}
+
+// canI asks the API server whether the current identity may access a resource, namespace is empty for
+// cluster-scoped resources and all namespaces.
+func canI(ctx context.Context, client *KubernetesClient, verb string, resource resource, subresource, namespace string) (bool, error) {
+	return reviewAccess(ctx, client, permission{verb: verb, resource: resource, subresource: subresource, namespace: namespace})
+}
+
+// reviewAccess asks the API server whether the current identity has a permission. Every permission is reviewed
+// once per client.
+func reviewAccess(ctx context.Context, client *KubernetesClient, key permission) (bool, error) {
+	client.reviewsMutex.Lock()
+	allowed, ok := client.reviews[key]
+	client.reviewsMutex.Unlock()
+	if ok {
+		return allowed, nil
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        key.verb,
+				Group:       key.resource.group,
+				Resource:    key.resource.name,
+				Subresource: key.subresource,
+				Namespace:   key.namespace,
+				Name:        key.name,
+			},
+		},
+	}
+
+	review, err := client.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("review access to %s: %v", key.resource, err)
+	}
+
+	client.reviewsMutex.Lock()
+	defer client.reviewsMutex.Unlock()
+
+	if client.reviews == nil {
+		client.reviews = map[permission]bool{}
+	}
+	client.reviews[key] = review.Status.Allowed
+
+	return review.Status.Allowed, nil
+}
+
+// resolveScope selects the namespaces to evaluate. Without explicitly selected namespaces all namespaces
+// are evaluated, unless the identity may not list pods in all namespaces, then its default namespace is used.
+func resolveScope(ctx context.Context, client *KubernetesClient, checks []check) ([]string, error) {
+	if len(client.namespaces) != 0 {
+		return client.namespaces, nil
+	}
+
+	if !slices.ContainsFunc(checks, check.namespaced) {
+		return []string{""}, nil
+	}
+
+	allowed, err := canI(ctx, client, "list", resourcePods, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed {
+		return []string{""}, nil
+	}
+
+	if client.defaultNamespace == "" {
+		return nil, fmt.Errorf("insufficient permissions to list pods in all namespaces and no default namespace is configured, select namespaces with --namespace")
+	}
+
+	return []string{client.defaultNamespace}, nil
+}
+
+// deniedResources reviews the access to the cluster-scoped resources of the checks.
+func deniedResources(ctx context.Context, client *KubernetesClient, checks []check) (map[resource]bool, error) {
+	denied := map[resource]bool{}
+	reviewed := map[resource]bool{}
+
+	for _, check := range checks {
+		for _, resource := range check.resources {
+			if resource.namespaced || reviewed[resource] {
+				continue
+			}
+			reviewed[resource] = true
+
+			allowed, err := canI(ctx, client, "list", resource, "", "")
+			if err != nil {
+				return nil, err
+			}
+
+			if !allowed {
+				denied[resource] = true
+			}
+		}
+	}
+
+	return denied, nil
+}
+
+// partialScope describes the namespace evaluated instead of all namespaces, it is empty unless the identity
+// may not list pods in all namespaces and no namespaces were selected.
+func (env *environment) partialScope() string {
+	if len(env.client.namespaces) != 0 || slices.Contains(env.namespaces, "") {
+		return ""
+	}
+
+	return fmt.Sprintf("- only namespace %s is evaluated, listing pods in all namespaces is not permitted\n", strings.Join(env.namespaces, ", "))
+}
+
+// inScope reports whether a namespace is evaluated.
+func (env *environment) inScope(namespace string) bool {
+	return slices.Contains(env.namespaces, "") || slices.Contains(env.namespaces, namespace)
+}
+
+// listInScope lists objects in every evaluated namespace.
+func listInScope[T any](env *environment, list func(namespace string) ([]T, error)) ([]T, error) {
+	items := []T{}
+
+	for _, namespace := range env.namespaces {
+		found, err := list(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, found...)
+	}
+
+	return items, nil
+}
+
+// insufficientPermissionsStatus replaces a check which may not read the cluster-scoped resources it needs.
+type insufficientPermissionsStatus struct {
+	resources []resource
+}
+
+func (env *environment) insufficientPermissions(check check) (status, bool) {
+	denied := []resource{}
+	for _, resource := range check.resources {
+		if env.denied[resource] {
+			denied = append(denied, resource)
+		}
+	}
+
+	if len(denied) == 0 {
+		return nil, false
+	}
+
+	return &insufficientPermissionsStatus{resources: denied}, true
+}
+
+func (s *insufficientPermissionsStatus) Summary(w io.Writer) error {
+	names := []string{}
+	for _, resource := range s.resources {
+		names = append(names, resource.String())
+	}
+
+	_, err := fmt.Fprintf(w, "Insufficient permissions to list %s, the check is skipped.\n", strings.Join(names, ", "))
+	return err
+}
+
+func (s *insufficientPermissionsStatus) Details(w io.Writer, colored bool) error {
+	return nil
+}
+
+func (s *insufficientPermissionsStatus) ExitCode() int {
+	return 0
+}
+
+func (s *insufficientPermissionsStatus) Severity() Severity {
+	return SeverityOK
+}
+
+func (s *insufficientPermissionsStatus) Report() report {
+	return report{}
+}
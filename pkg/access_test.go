@@ -0,0 +1,107 @@
+package k8status
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func Test_environment_inScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces []string
+		namespace  string
+		want       bool
+	}{
+		{name: "all namespaces", namespaces: []string{""}, namespace: "team-a", want: true},
+		{name: "selected namespace", namespaces: []string{"team-a", "team-b"}, namespace: "team-b", want: true},
+		{name: "other namespace", namespaces: []string{"team-a"}, namespace: "team-b", want: false},
+		{name: "cluster-scoped object", namespaces: []string{"team-a"}, namespace: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &environment{namespaces: tt.namespaces}
+			got := env.inScope(tt.namespace)
+			if got != tt.want {
+				t.Errorf("environment.inScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_environment_insufficientPermissions(t *testing.T) {
+	env := &environment{
+		denied: map[resource]bool{resourceNamespaces: true},
+	}
+
+	_, denied := env.insufficientPermissions(check{resources: []resource{resourceNodes}})
+	if denied {
+		t.Errorf("environment.insufficientPermissions() denied a check with permitted resources")
+	}
+
+	status, denied := env.insufficientPermissions(check{resources: []resource{resourceNamespaces, resourcePods}})
+	if !denied {
+		t.Fatalf("environment.insufficientPermissions() permitted a check with denied resources")
+	}
+
+	if status.Severity() != SeverityOK || status.ExitCode() != 0 {
+		t.Errorf("skipped check = %v, %d, want OK, 0", status.Severity(), status.ExitCode())
+	}
+
+	summary := &bytes.Buffer{}
+	err := status.Summary(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Insufficient permissions to list namespaces, the check is skipped.\n"
+	if summary.String() != want {
+		t.Errorf("skipped check summary = %q, want %q", summary.String(), want)
+	}
+}
+
+func Test_environment_partialScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		selected   []string
+		namespaces []string
+		want       string
+	}{
+		{name: "all namespaces", namespaces: []string{""}, want: ""},
+		{name: "selected namespaces", selected: []string{"team-a"}, namespaces: []string{"team-a"}, want: ""},
+		{
+			name:       "default namespace",
+			namespaces: []string{"team-a"},
+			want:       "- only namespace team-a is evaluated, listing pods in all namespaces is not permitted\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &environment{client: &KubernetesClient{namespaces: tt.selected}, namespaces: tt.namespaces}
+			got := env.partialScope()
+			if got != tt.want {
+				t.Errorf("environment.partialScope() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_canI_reviewedOnce(t *testing.T) {
+	// without a clientset every access review which is not remembered would panic
+	client := &KubernetesClient{
+		reviews: map[permission]bool{
+			{verb: "list", resource: resourcePods}:  true,
+			{verb: "list", resource: resourceNodes}: false,
+		},
+	}
+
+	allowed, err := canI(context.Background(), client, "list", resourcePods, "", "")
+	if err != nil || !allowed {
+		t.Errorf("canI(list pods) = %v, %v, want true", allowed, err)
+	}
+
+	allowed, err = canI(context.Background(), client, "list", resourceNodes, "", "")
+	if err != nil || allowed {
+		t.Errorf("canI(list nodes) = %v, %v, want false", allowed, err)
+	}
+}
@@ -0,0 +1,219 @@
+package k8status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// changeDebounce gives related updates (e.g. all pods of a rollout) time to arrive
+	// before the checks are evaluated again.
+	changeDebounce = 2 * time.Second
+)
+
+type clusterCache struct {
+	factory informers.SharedInformerFactory
+	// events caches the Warning events only, they explain failures and do not trigger evaluations.
+	events  informers.SharedInformerFactory
+	changes chan struct{}
+	// debounce is the time waited after a change before the checks are evaluated.
+	debounce time.Duration
+	// resources lists the cached resources.
+	resources map[resource]bool
+}
+
+// StartCache starts shared informers for every resource type read by the given checks.
+// Afterwards the checks read from local listers instead of listing the resources from the API server.
+// Resources the identity may not list and watch in all namespaces are not cached.
+func (client *KubernetesClient) StartCache(ctx context.Context, checks []check) error {
+	read := readResources(checks)
+
+	factory := informers.NewSharedInformerFactory(client.clientset, 0)
+
+	informerFactories := map[resource]func() cache.SharedIndexInformer{
+		resourceNodes:                  factory.Core().V1().Nodes().Informer,
+		resourceNamespaces:             factory.Core().V1().Namespaces().Informer,
+		resourcePersistentVolumes:      factory.Core().V1().PersistentVolumes().Informer,
+		resourcePersistentVolumeClaims: factory.Core().V1().PersistentVolumeClaims().Informer,
+		resourcePods:                   factory.Core().V1().Pods().Informer,
+		resourceDeployments:            factory.Apps().V1().Deployments().Informer,
+		resourceStatefulSets:           factory.Apps().V1().StatefulSets().Informer,
+		resourceDaemonSets:             factory.Apps().V1().DaemonSets().Informer,
+		resourceReplicaSets:            factory.Apps().V1().ReplicaSets().Informer,
+		resourceJobs:                   factory.Batch().V1().Jobs().Informer,
+		resourceCronJobs:               factory.Batch().V1().CronJobs().Informer,
+	}
+
+	resources := map[resource]bool{}
+	watched := []cache.SharedIndexInformer{}
+	for resource, informer := range informerFactories {
+		if !read[resource] {
+			continue
+		}
+
+		cacheable, err := canCache(ctx, client, resource)
+		if err != nil {
+			return err
+		}
+
+		if !cacheable {
+			continue
+		}
+
+		resources[resource] = true
+		watched = append(watched, informer())
+	}
+
+	var events informers.SharedInformerFactory
+	if read[resourceEvents] {
+		cacheable, err := canCache(ctx, client, resourceEvents)
+		if err != nil {
+			return err
+		}
+
+		if cacheable {
+			events = informers.NewSharedInformerFactoryWithOptions(client.clientset, 0,
+				informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+					options.FieldSelector = "type=" + v1.EventTypeWarning
+				}),
+			)
+			resources[resourceEvents] = true
+			events.Core().V1().Events().Informer()
+		}
+	}
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify() },
+		UpdateFunc: func(oldObj, newObj interface{}) { notify() },
+		DeleteFunc: func(obj interface{}) { notify() },
+	}
+
+	for _, informer := range watched {
+		_, err := informer.AddEventHandler(handler)
+		if err != nil {
+			return fmt.Errorf("register cache event handler: %v", err)
+		}
+	}
+
+	factories := []informers.SharedInformerFactory{factory}
+	if events != nil {
+		factories = append(factories, events)
+	}
+
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
+
+	for _, factory := range factories {
+		for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				return fmt.Errorf("sync cache for %v", informerType)
+			}
+		}
+	}
+
+	// the initial sync is not a change
+	select {
+	case <-changes:
+	default:
+	}
+
+	client.cache = &clusterCache{
+		factory:   factory,
+		events:    events,
+		changes:   changes,
+		debounce:  changeDebounce,
+		resources: resources,
+	}
+
+	return nil
+}
+
+// readResources lists the resources read by the checks.
+func readResources(checks []check) map[resource]bool {
+	read := map[resource]bool{}
+
+	for _, check := range checks {
+		for _, resource := range check.resources {
+			read[resource] = true
+		}
+	}
+
+	return read
+}
+
+func canCache(ctx context.Context, client *KubernetesClient, resource resource) (bool, error) {
+	for _, verb := range []string{"list", "watch"} {
+		allowed, err := canI(ctx, client, verb, resource, "", "")
+		if err != nil || !allowed {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// cached reports whether a resource is read from the cache.
+func (client *KubernetesClient) cached(resource resource) bool {
+	return client.cache != nil && client.cache.resources[resource]
+}
+
+// waitForChange blocks until the interval passed since the last evaluation started, or the cache observed a change
+// and at least minInterval passed since the last evaluation. Busy clusters change constantly, minInterval keeps them
+// from being evaluated back to back. It reports whether the next evaluation is due to a change, ok is false if the
+// context was cancelled.
+func (client *KubernetesClient) waitForChange(ctx context.Context, last time.Time, interval, minInterval time.Duration) (changed bool, ok bool) {
+	var changes <-chan struct{}
+	debounce := time.Duration(0)
+	if client.cache != nil {
+		changes = client.cache.changes
+		debounce = client.cache.debounce
+	}
+
+	timer := time.NewTimer(time.Until(last.Add(interval)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false, false
+	case <-timer.C:
+		return false, true
+	case <-changes:
+	}
+
+	delay := time.NewTimer(max(debounce, time.Until(last.Add(minInterval))))
+	defer delay.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false, false
+	case <-timer.C:
+		return false, true
+	case <-delay.C:
+		return true, true
+	}
+}
+
+func values[T any](items []*T) []T {
+	result := make([]T, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, *item)
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package k8status
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_waitForChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      bool
+		cancel      bool
+		interval    time.Duration
+		minInterval time.Duration
+		wantChanged bool
+		wantOK      bool
+		wantAfter   time.Duration
+	}{
+		{name: "interval", interval: 20 * time.Millisecond, minInterval: 10 * time.Millisecond, wantOK: true, wantAfter: 20 * time.Millisecond},
+		{name: "change after min interval", change: true, interval: time.Minute, minInterval: 60 * time.Millisecond, wantChanged: true, wantOK: true, wantAfter: 60 * time.Millisecond},
+		{name: "interval before min interval", change: true, interval: 50 * time.Millisecond, minInterval: time.Minute, wantOK: true, wantAfter: 50 * time.Millisecond},
+		{name: "cancelled", change: true, cancel: true, interval: time.Minute, minInterval: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := make(chan struct{}, 1)
+			if tt.change {
+				changes <- struct{}{}
+			}
+			client := &KubernetesClient{cache: &clusterCache{changes: changes, debounce: 5 * time.Millisecond}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			last := time.Now()
+			changed, ok := client.waitForChange(ctx, last, tt.interval, tt.minInterval)
+			waited := time.Since(last)
+
+			if changed != tt.wantChanged || ok != tt.wantOK {
+				t.Errorf("waitForChange() = %v, %v, want %v, %v", changed, ok, tt.wantChanged, tt.wantOK)
+			}
+			if waited < tt.wantAfter {
+				t.Errorf("waitForChange() returned after %v, want at least %v", waited, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func Test_readResources(t *testing.T) {
+	tests := []struct {
+		name string
+		only []string
+		want map[resource]bool
+	}{
+		{name: "nodes", only: []string{"nodes"}, want: map[resource]bool{resourceNodes: true}},
+		{
+			name: "deployments",
+			only: []string{"deployments"},
+			want: map[resource]bool{resourceDeployments: true, resourceEvents: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := selectChecks(tt.only, nil, DefaultConfig())
+			if err != nil {
+				t.Fatalf("selectChecks() error = %v", err)
+			}
+
+			if got := readResources(checks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readResources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,10 +10,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const (
-	cassandraNamespace = "cassandra"
-)
-
 type cassandraStatus struct {
 	found          bool
 	total          int
@@ -22,8 +18,8 @@ type cassandraStatus struct {
 	details        string
 }
 
-func NewCassandraStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	exists, err := namespaceExists(ctx, client.clientset, cassandraNamespace)
+func NewCassandraStatus(ctx context.Context, env *environment) (status, error) {
+	exists, err := namespaceExists(ctx, env.client, env.config.Cassandra.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +32,7 @@ func NewCassandraStatus(ctx context.Context, client *KubernetesClient) (status,
 		return status, nil
 	}
 
-	readyNodes, totalNodes, details, err := getCassandraNodeStatus(ctx, client)
+	readyNodes, totalNodes, details, err := getCassandraNodeStatus(ctx, env.client, env.config.Cassandra)
 	if err != nil {
 		return nil, err
 	}
@@ -69,19 +65,68 @@ func (s *cassandraStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *cassandraStatus) ExitCode() int {
+	if s.Severity() == SeverityCritical {
+		return exitCodeCassandra
+	}
+
+	return 0
+}
+
+func (s *cassandraStatus) Severity() Severity {
 	if !s.found {
-		return 0
+		return SeverityOK
 	}
 
-	if s.unhealthyCount > 0 {
-		return 46
+	return countedSeverity(s.unhealthyCount, 0, 0)
+}
+
+func (s *cassandraStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthyCount,
+		Unhealthy: s.unhealthyCount,
+		Details:   s.toTable(),
 	}
+}
 
-	return 0
+func (s *cassandraStatus) toTable() Table {
+	header := []string{"Node Status"}
+
+	rows := [][]string{}
+	if s.unhealthyCount == 0 {
+		return Table{
+			Header: header,
+			Rows:   rows,
+		}
+	}
+
+	severities := []Severity{}
+	for _, line := range strings.Split(strings.TrimSpace(s.details), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "UN") {
+			continue
+		}
+
+		rows = append(rows, []string{line})
+		severities = append(severities, SeverityCritical)
+	}
+
+	return Table{
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
+	}
 }
 
-func getCassandraNodeStatus(ctx context.Context, client *KubernetesClient) (int, int, string, error) {
-	username, password, err := getCasssandraCredentials(ctx, client)
+func cassandraPermissions(config Config) []permission {
+	return []permission{
+		{verb: "get", resource: resourceSecrets, namespace: config.Cassandra.Namespace, name: config.Cassandra.Secret},
+		{verb: "create", resource: resourcePods, subresource: "exec", namespace: config.Cassandra.Namespace},
+	}
+}
+
+func getCassandraNodeStatus(ctx context.Context, client *KubernetesClient, config CassandraConfig) (int, int, string, error) {
+	username, password, err := getCasssandraCredentials(ctx, client, config)
 	if err != nil {
 		return 0, 0, "", err
 	}
@@ -90,15 +135,16 @@ func getCassandraNodeStatus(ctx context.Context, client *KubernetesClient) (int,
 	command := fmt.Sprintf("nodetool -u %s -pw %s --host ::FFFF:127.0.0.1 status | grep --extended-regexp '^[UD][NLJM]\\s+'", username, password)
 
 	err = exec(
+		ctx,
 		client,
-		cassandraNamespace,
-		"k8ssandra-dc1-default-sts-0",
-		"cassandra",
+		config.Namespace,
+		config.Pod,
+		config.Container,
 		command,
 		outputBytes,
 	)
 	if err != nil {
-		return 0, 0, "", err
+		return 0, 0, "", fmt.Errorf("execute nodetool status in cassandra pod: %v", err)
 	}
 
 	output := outputBytes.String()
@@ -108,8 +154,8 @@ func getCassandraNodeStatus(ctx context.Context, client *KubernetesClient) (int,
 	return readyNodes, totalNodes, output, nil
 }
 
-func getCasssandraCredentials(ctx context.Context, client *KubernetesClient) (string, string, error) {
-	secret, err := client.clientset.CoreV1().Secrets(cassandraNamespace).Get(ctx, "k8ssandra-superuser", metav1.GetOptions{})
+func getCasssandraCredentials(ctx context.Context, client *KubernetesClient, config CassandraConfig) (string, string, error) {
+	secret, err := client.clientset.CoreV1().Secrets(config.Namespace).Get(ctx, config.Secret, metav1.GetOptions{})
 	if err != nil {
 		return "", "", err
 	}
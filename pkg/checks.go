@@ -0,0 +1,183 @@
+package k8status
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type check struct {
+	name        string
+	description string
+	exitCode    int
+	exitBit     int
+	// resources lists the API resources read by the check, namespaced resources are read in the evaluated namespaces.
+	resources []resource
+	// access lists further permissions needed by the check, e.g. to exec into pods.
+	access func(config Config) []permission
+	// polled checks exec into pods, watch and serve evaluate them on the interval only and not on changes.
+	polled bool
+	status newStatus
+}
+
+// namespaced reports whether the check reads namespaced resources.
+func (c check) namespaced() bool {
+	return slices.ContainsFunc(c.resources, func(resource resource) bool { return resource.namespaced })
+}
+
+var checks = []check{
+	{
+		name:        "nodes",
+		description: "Nodes are ready and schedulable.",
+		exitCode:    exitCodeNodes,
+		exitBit:     exitBitNodes,
+		resources:   []resource{resourceNodes},
+		status:      NewNodeStatus,
+	},
+	{
+		name:        "cassandra",
+		description: "Cassandra nodes report up and normal via nodetool.",
+		exitCode:    exitCodeCassandra,
+		exitBit:     exitBitStorage,
+		resources:   []resource{resourceNamespaces},
+		access:      cassandraPermissions,
+		polled:      true,
+		status:      NewCassandraStatus,
+	},
+	{
+		name:        "rook-ceph",
+		description: "Ceph reports HEALTH_OK via the rook-ceph tools pod.",
+		exitCode:    exitCodeRookCeph,
+		exitBit:     exitBitStorage,
+		resources:   []resource{resourceNamespaces},
+		access:      rookCephPermissions,
+		polled:      true,
+		status:      NewRookCephStatus,
+	},
+	{
+		name:        "volumes",
+		description: "Persistent volumes are bound or available.",
+		exitCode:    exitCodeVolumes,
+		exitBit:     exitBitStorage,
+		resources:   []resource{resourcePersistentVolumes},
+		status:      NewVolumesStatus,
+	},
+	{
+		name:        "volume-claims",
+		description: "Persistent volume claims are bound.",
+		exitCode:    exitCodeVolumeClaims,
+		exitBit:     exitBitStorage,
+		resources:   []resource{resourcePersistentVolumeClaims, resourceEvents},
+		status:      NewVolumeClaimsStatus,
+	},
+	{
+		name:        "namespaces",
+		description: "Namespaces are active.",
+		exitCode:    exitCodeNamespaces,
+		exitBit:     exitBitNamespaces,
+		resources:   []resource{resourceNamespaces},
+		status:      NewNamespacesStatus,
+	},
+	{
+		name:        "daemonsets",
+		description: "Daemonsets run an up-to-date, ready pod on every scheduled node.",
+		exitCode:    exitCodeDaemonsets,
+		exitBit:     exitBitWorkloads,
+		resources:   []resource{resourceDaemonSets, resourceEvents},
+		status:      NewDaemonsetsStatus,
+	},
+	{
+		name:        "statefulsets",
+		description: "Statefulsets have all replicas ready and updated.",
+		exitCode:    exitCodeStatefulsets,
+		exitBit:     exitBitWorkloads,
+		resources:   []resource{resourceStatefulSets, resourceEvents},
+		status:      NewStatefulsetsStatus,
+	},
+	{
+		name:        "deployments",
+		description: "Deployments have all replicas ready, available and updated.",
+		exitCode:    exitCodeDeployments,
+		exitBit:     exitBitWorkloads,
+		resources:   []resource{resourceDeployments, resourceEvents},
+		status:      NewDeploymentsStatus,
+	},
+	{
+		name:        "cronjobs",
+		description: "Cronjobs did not miss too many scheduled runs.",
+		exitCode:    exitCodeCronjobs,
+		exitBit:     exitBitJobs,
+		resources:   []resource{resourceCronJobs, resourceEvents},
+		status:      NewCronjobsStatus,
+	},
+	{
+		name:        "jobs",
+		description: "Jobs are active or completed.",
+		exitCode:    exitCodeJobs,
+		exitBit:     exitBitJobs,
+		resources:   []resource{resourceJobs, resourceEvents},
+		status:      NewJobsStatus,
+	},
+	{
+		name:        "pods",
+		description: "Pods have all containers ready or succeeded.",
+		exitCode:    exitCodePods,
+		exitBit:     exitBitWorkloads,
+		resources: []resource{
+			resourcePods, resourceReplicaSets, resourceJobs, resourceDeployments, resourceStatefulSets,
+			resourceDaemonSets, resourceCronJobs, resourceEvents,
+		},
+		status: NewPodsStatus,
+	},
+}
+
+// CheckNames lists the IDs of all available checks.
+func CheckNames() []string {
+	names := []string{}
+
+	for _, check := range checks {
+		names = append(names, check.name)
+	}
+
+	return names
+}
+
+// selectChecks returns the checks named in only (all checks enabled by the config if empty)
+// without the checks named in skip.
+func selectChecks(only []string, skip []string, config Config) ([]check, error) {
+	for _, name := range append(append([]string{}, only...), skip...) {
+		if !isCheck(name) {
+			return nil, fmt.Errorf("unknown check %q, expected one of %s", name, strings.Join(CheckNames(), ", "))
+		}
+	}
+
+	selected := []check{}
+
+	for _, check := range checks {
+		if len(only) != 0 && !slices.Contains(only, check.name) {
+			continue
+		}
+
+		if len(only) == 0 && !config.enabled(check.name) {
+			continue
+		}
+
+		if slices.Contains(skip, check.name) {
+			continue
+		}
+
+		selected = append(selected, check)
+	}
+
+	return selected, nil
+}
+
+func isCheck(name string) bool {
+	for _, check := range checks {
+		if check.name == name {
+			return true
+		}
+	}
+
+	return false
+}
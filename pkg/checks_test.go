@@ -0,0 +1,76 @@
+package k8status
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_selectChecks(t *testing.T) {
+	disabled := false
+	enabled := true
+
+	config := DefaultConfig()
+	config.Checks = map[string]CheckConfig{
+		"cassandra": {Enabled: &disabled},
+		"rook-ceph": {Enabled: &disabled},
+		"nodes":     {Enabled: &enabled},
+	}
+
+	all := CheckNames()
+
+	tests := []struct {
+		name    string
+		only    []string
+		skip    []string
+		config  Config
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", config: DefaultConfig(), want: all},
+		{name: "disabled by config", config: config, want: append([]string{"nodes"}, all[3:]...)},
+		{name: "only", only: []string{"pods", "nodes"}, config: DefaultConfig(), want: []string{"nodes", "pods"}},
+		{name: "only overrides disabled", only: []string{"cassandra"}, config: config, want: []string{"cassandra"}},
+		{name: "skip", skip: []string{"pods", "jobs"}, config: config, want: []string{"nodes", "volumes", "volume-claims", "namespaces", "daemonsets", "statefulsets", "deployments", "cronjobs"}},
+		{name: "only and skip", only: []string{"nodes", "pods"}, skip: []string{"pods"}, config: DefaultConfig(), want: []string{"nodes"}},
+		{name: "unknown only", only: []string{"node"}, config: DefaultConfig(), wantErr: true},
+		{name: "unknown skip", skip: []string{"ceph"}, config: DefaultConfig(), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectChecks(tt.only, tt.skip, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := []string{}
+			for _, check := range selected {
+				got = append(got, check.name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_check_namespaced(t *testing.T) {
+	want := map[string]bool{
+		"nodes":     false,
+		"cassandra": false,
+		// the tools pod is looked up in the rook-ceph namespace, not in the evaluated namespaces
+		"rook-ceph": false,
+		"volumes":   false,
+		"pods":      true,
+	}
+
+	for _, check := range checks {
+		namespaced, ok := want[check.name]
+		if ok && check.namespaced() != namespaced {
+			t.Errorf("%s namespaced() = %v, want %v", check.name, check.namespaced(), namespaced)
+		}
+	}
+}
@@ -7,43 +7,66 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type volumeClaimsStatus struct {
+	policy    *policy
+	events    eventIndex
 	total     int
 	ignored   int
+	warnings  int
 	healthy   int
 	claims    []v1.PersistentVolumeClaim
 	unhealthy int
 }
 
-func NewVolumeClaimsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	pvcsList, err := client.clientset.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+func NewVolumeClaimsStatus(ctx context.Context, env *environment) (status, error) {
+	pvcs, err := listInScope(env, func(namespace string) ([]v1.PersistentVolumeClaim, error) {
+		return listPersistentVolumeClaims(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	pvcs := pvcsList.Items
-
 	status := &volumeClaimsStatus{
+		policy: env.policy,
 		claims: []v1.PersistentVolumeClaim{},
 	}
 	status.add(pvcs)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *volumeClaimsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d volume claims are bound.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d volume claims are bound.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
-func printSummaryWithIgnored(w io.Writer, phrase string, ignored int, args ...any) error {
+func printSummary(w io.Writer, phrase string, ignored int, warnings int, args ...any) error {
+	exceptions := []string{}
+
 	if ignored != 0 {
-		phrase = strings.ReplaceAll(phrase, ".\n", " (%d ignored).\n")
+		exceptions = append(exceptions, "%d ignored")
 		args = append(args, ignored)
 	}
 
+	if warnings == 1 {
+		exceptions = append(exceptions, "%d warning")
+		args = append(args, warnings)
+	}
+
+	if warnings > 1 {
+		exceptions = append(exceptions, "%d warnings")
+		args = append(args, warnings)
+	}
+
+	if len(exceptions) != 0 {
+		phrase = strings.ReplaceAll(phrase, ".\n", " ("+strings.Join(exceptions, ", ")+").\n")
+	}
+
 	_, err := fmt.Fprintf(w, phrase, args...)
 	return err
 }
@@ -53,25 +76,48 @@ func (s *volumeClaimsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *volumeClaimsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 43
+	if s.Severity() == SeverityCritical {
+		return exitCodeVolumeClaims
 	}
 
 	return 0
 }
 
+func (s *volumeClaimsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *volumeClaimsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *volumeClaimsStatus) toTable() Table {
-	header := []string{"Namespace", "Volume Claim", "Phase"}
+	header := []string{"Namespace", "Volume Claim", "Phase", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.claims {
-		row := []string{item.Namespace, item.Name, string(item.Status.Phase)}
+		row := []string{
+			item.Namespace,
+			item.Name,
+			string(item.Status.Phase),
+			s.events.last(item.UID),
+		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -84,8 +130,11 @@ func (s *volumeClaimsStatus) add(pvcs []v1.PersistentVolumeClaim) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.claims = append(s.claims, item)
@@ -4,23 +4,51 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 const (
 	inClusterSentinelFile = "/run/secrets/kubernetes.io/serviceaccount/namespace"
 )
 
+// ClientOptions selects the cluster to connect to like the corresponding kubectl flags.
+type ClientOptions struct {
+	// Kubeconfig is the path of the kube config file, the files of $KUBECONFIG or ~/.kube/config are merged if empty.
+	Kubeconfig string
+	Context    string
+	Cluster    string
+	User       string
+	// Namespaces limits the namespaced checks to some namespaces. If empty, all namespaces are evaluated
+	// or the default namespace of the context if the identity may not list pods in all namespaces.
+	Namespaces []string
+}
+
 type KubernetesClient struct {
 	restconfig *rest.Config
 	clientset  *kubernetes.Clientset
+	cache      *clusterCache
+	// namespaces scope the namespaced checks, all namespaces are evaluated if empty.
+	namespaces []string
+	// defaultNamespace is the namespace of the kube config context or the service account.
+	defaultNamespace string
+
+	// reviews remembers the access reviews, permissions do not change between evaluations.
+	reviewsMutex sync.Mutex
+	reviews      map[permission]bool
+
+	restarts restartHistory
 }
 
-func NewKubernetesClient(kubeconfigFile string) (*KubernetesClient, error) {
-	restconfig, err := restConfig(kubeconfigFile)
+// NewKubernetesClient connects to the cluster selected by the options.
+func NewKubernetesClient(options ClientOptions) (*KubernetesClient, error) {
+	restconfig, defaultNamespace, err := restConfig(options)
 	if err != nil {
 		return nil, fmt.Errorf("load kubernetes client config: %v", err)
 	}
@@ -31,22 +59,29 @@ func NewKubernetesClient(kubeconfigFile string) (*KubernetesClient, error) {
 	}
 
 	return &KubernetesClient{
-		restconfig,
-		clientset,
+		restconfig:       restconfig,
+		clientset:        clientset,
+		namespaces:       options.Namespaces,
+		defaultNamespace: defaultNamespace,
 	}, nil
 }
 
-func restConfig(kubeConfigFile string) (*rest.Config, error) {
+func restConfig(options ClientOptions) (*rest.Config, string, error) {
+	// explicitly selected kube configs take precedence over the service account
+	if options.Kubeconfig != "" || options.Context != "" || options.Cluster != "" || options.User != "" {
+		return localKubeConfig(options)
+	}
+
 	inCluster, err := hasInClusterConfig()
 	if err != nil {
-		return nil, fmt.Errorf("look up in cluster config: %v", err)
+		return nil, "", fmt.Errorf("look up in cluster config: %v", err)
 	}
 
 	if inCluster {
 		return inClusterConfig()
 	}
 
-	return localKubeConfig(kubeConfigFile)
+	return localKubeConfig(options)
 }
 
 func hasInClusterConfig() (bool, error) {
@@ -63,20 +98,64 @@ func hasInClusterConfig() (bool, error) {
 	return false, err
 }
 
-func inClusterConfig() (*rest.Config, error) {
+func inClusterConfig() (*rest.Config, string, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
-		return nil, fmt.Errorf("load in cluster config: %v", err)
+		return nil, "", fmt.Errorf("load in cluster config: %v", err)
 	}
 
-	return config, nil
+	namespace, err := os.ReadFile(inClusterSentinelFile)
+	if err != nil {
+		return nil, "", fmt.Errorf("read service account namespace: %v", err)
+	}
+
+	return config, strings.TrimSpace(string(namespace)), nil
 }
 
-func localKubeConfig(kubeConfigFile string) (*rest.Config, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeConfigFile)
+func localKubeConfig(options ClientOptions) (*rest.Config, string, error) {
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: options.Context,
+		Context: clientcmdapi.Context{
+			Cluster:  options.Cluster,
+			AuthInfo: options.User,
+		},
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules(options), overrides)
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("load local kube config: %v", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("look up namespace of kube config context: %v", err)
+	}
+
+	return config, namespace, nil
+}
+
+// loadingRules merges the kube config files like kubectl does.
+func loadingRules(options ClientOptions) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = options.Kubeconfig
+
+	return rules
+}
+
+// KubeContexts lists the names of all contexts of the merged kube config.
+func KubeContexts(options ClientOptions) ([]string, error) {
+	config, err := loadingRules(options).Load()
 	if err != nil {
 		return nil, fmt.Errorf("load local kube config: %v", err)
 	}
 
-	return config, nil
+	contexts := []string{}
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, nil
 }
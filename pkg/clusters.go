@@ -0,0 +1,198 @@
+package k8status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Cluster is a kubernetes cluster evaluated in multi-cluster mode, it is named after its kubeconfig context.
+type Cluster struct {
+	Name   string
+	Client *KubernetesClient
+}
+
+type clusterResults struct {
+	name    string
+	results results
+}
+
+type clustersDocument struct {
+	Time     time.Time         `json:"time"`
+	Severity Severity          `json:"severity"`
+	ExitCode int               `json:"exitCode"`
+	Clusters []clusterDocument `json:"clusters"`
+}
+
+type clusterDocument struct {
+	Name     string          `json:"name"`
+	Severity Severity        `json:"severity"`
+	ExitCode int             `json:"exitCode"`
+	Checks   []checkDocument `json:"checks"`
+}
+
+// RunClusters evaluates the checks against several clusters in parallel. The text report starts with
+// a matrix of the clusters and checks followed by the details grouped by cluster.
+func RunClusters(ctx context.Context, clusters []Cluster, options Options) error {
+	output := options.Output
+
+	checks, exitCodeMode, err := options.prepare()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if output == OutputText {
+		fmt.Println(now.Format("2006-01-02 15:04:05"))
+	}
+
+	futures := []chan results{}
+	for _, cluster := range clusters {
+		future := make(chan results, 1)
+		futures = append(futures, future)
+
+		go func(future chan results, client *KubernetesClient) {
+			future <- runChecks(ctx, client, checks, &options.Config, options.Colored)
+		}(future, cluster.Client)
+	}
+
+	all := []clusterResults{}
+	for i, future := range futures {
+		results := <-future
+		for _, result := range results {
+			result.cluster = clusters[i].Name
+		}
+
+		all = append(all, clusterResults{name: clusters[i].Name, results: results})
+	}
+
+	err = printClusters(os.Stdout, all, checks, output, exitCodeMode, options.Colored, now)
+	if err != nil {
+		return err
+	}
+
+	return exit(flattenClusters(all), output, exitCodeMode)
+}
+
+func flattenClusters(clusters []clusterResults) results {
+	flat := results{}
+	for _, cluster := range clusters {
+		flat = append(flat, cluster.results...)
+	}
+
+	return flat
+}
+
+func printClusters(w io.Writer, clusters []clusterResults, checks []check, output, exitCodeMode string, colored bool, now time.Time) error {
+	switch output {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(clustersDocumentOf(clusters, now, exitCodeMode))
+	case OutputYAML:
+		out, err := yaml.Marshal(clustersDocumentOf(clusters, now, exitCodeMode))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(out)
+		return err
+	case OutputJUnit, OutputNagios:
+		return flattenClusters(clusters).Print(w, output, exitCodeMode, now)
+	default:
+		return clustersText(w, clusters, checks, colored)
+	}
+}
+
+func clustersDocumentOf(clusters []clusterResults, now time.Time, exitCodeMode string) clustersDocument {
+	flat := flattenClusters(clusters)
+
+	doc := clustersDocument{
+		Time:     now,
+		Severity: flat.Severity(),
+		ExitCode: flat.exitCodeFor(exitCodeMode),
+		Clusters: []clusterDocument{},
+	}
+
+	for _, cluster := range clusters {
+		clusterDoc := clusterDocument{
+			Name:     cluster.name,
+			Severity: cluster.results.Severity(),
+			ExitCode: cluster.results.exitCodeFor(exitCodeMode),
+			Checks:   []checkDocument{},
+		}
+
+		for _, result := range cluster.results {
+			clusterDoc.Checks = append(clusterDoc.Checks, result.Document())
+		}
+
+		doc.Clusters = append(doc.Clusters, clusterDoc)
+	}
+
+	return doc
+}
+
+func clustersText(w io.Writer, clusters []clusterResults, checks []check, colored bool) error {
+	err := clustersMatrix(clusters, checks).Fprint(w, colored)
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range clusters {
+		_, err := fmt.Fprintf(w, "\n=== %s ===\n", cluster.name)
+		if err != nil {
+			return err
+		}
+
+		err = cluster.results.Text(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clustersMatrix tabulates the severity and healthy objects of every check per cluster.
+func clustersMatrix(clusters []clusterResults, checks []check) Table {
+	header := []string{"Cluster"}
+	for _, check := range checks {
+		header = append(header, check.name)
+	}
+
+	rows := [][]string{}
+	for _, cluster := range clusters {
+		row := []string{cluster.name}
+
+		for _, check := range checks {
+			result := cluster.results.find(check.name)
+			row = append(row, matrixCell(result))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return Table{
+		Header: header,
+		Rows:   rows,
+	}
+}
+
+func matrixCell(result *result) string {
+	if result == nil || result.err != nil {
+		return SeverityUnknown.String()
+	}
+
+	if result.report.Total == 0 {
+		return result.severity.String()
+	}
+
+	return fmt.Sprintf("%s %d/%d", result.severity, result.report.Healthy, result.report.Total)
+}
@@ -0,0 +1,40 @@
+package k8status
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_clustersMatrix(t *testing.T) {
+	clusters := []clusterResults{
+		{
+			name: "prod",
+			results: results{
+				{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}},
+				{name: "pods", severity: SeverityCritical, report: report{Total: 10, Healthy: 8, Unhealthy: 2}},
+			},
+		},
+		{
+			name: "staging",
+			results: results{
+				{name: "nodes", severity: SeverityUnknown, err: errors.New("connection refused")},
+				{name: "pods", severity: SeverityOK},
+			},
+		},
+	}
+	checks := []check{{name: "nodes"}, {name: "pods"}}
+
+	want := Table{
+		Header: []string{"Cluster", "nodes", "pods"},
+		Rows: [][]string{
+			{"prod", "OK 3/3", "CRITICAL 8/10"},
+			{"staging", "UNKNOWN", "OK"},
+		},
+	}
+
+	got := clustersMatrix(clusters, checks)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("clustersMatrix() = %v, want %v", got, want)
+	}
+}
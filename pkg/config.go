@@ -0,0 +1,225 @@
+package k8status
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Config tunes the behaviour of the checks. It is loaded from a yaml file, unset fields keep their defaults.
+type Config struct {
+	// Output is the default output format.
+	Output string `json:"output,omitempty"`
+	// Timeout limits the evaluation of all checks, checks still running afterwards are reported as timed out.
+	Timeout metav1.Duration `json:"timeout"`
+	// MinInterval is the minimum time between two evaluations of watch and serve triggered by changes of the cluster.
+	MinInterval metav1.Duration `json:"minInterval"`
+	// Checks enables or disables checks by name.
+	Checks map[string]CheckConfig `json:"checks,omitempty"`
+	// Ignore lists rules matching objects whose unhealthy state does not fail the report.
+	Ignore    []IgnoreRule    `json:"ignore"`
+	Cassandra CassandraConfig `json:"cassandra"`
+	RookCeph  RookCephConfig  `json:"rookCeph"`
+	Cronjobs  CronjobsConfig  `json:"cronjobs"`
+	Pods      PodsConfig      `json:"pods"`
+	Nodes     NodesConfig     `json:"nodes"`
+}
+
+type CheckConfig struct {
+	Enabled *bool `json:"enabled,omitempty"`
+	// Timeout limits the evaluation of this check, it defaults to the overall timeout.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type CassandraConfig struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Secret    string `json:"secret"`
+}
+
+type RookCephConfig struct {
+	Namespace string `json:"namespace"`
+	// ToolsSelector is the label selector of the rook-ceph tools pod.
+	ToolsSelector string `json:"toolsSelector"`
+}
+
+type PodsConfig struct {
+	// RestartWindow is the time span in which restarts are counted.
+	RestartWindow metav1.Duration `json:"restartWindow"`
+	// MinRestarts is the number of restarts within the restart window from which a ready pod is reported.
+	MinRestarts int32 `json:"minRestarts"`
+	// PendingGracePeriod is how long a pod may be pending before it is unhealthy.
+	PendingGracePeriod metav1.Duration `json:"pendingGracePeriod"`
+	// TerminatingGracePeriod is how long a pod may be terminating beyond its own termination grace period.
+	TerminatingGracePeriod metav1.Duration `json:"terminatingGracePeriod"`
+	// HideOwnedByUnhealthy omits pods from the details whose owner is reported unhealthy by another check.
+	HideOwnedByUnhealthy bool `json:"hideOwnedByUnhealthy"`
+}
+
+type NodesConfig struct {
+	// HeartbeatTimeout is how old the last heartbeat of a node may be, kubelets report unchanged conditions every 5m.
+	HeartbeatTimeout metav1.Duration `json:"heartbeatTimeout"`
+	// MaxKubeletSkew is the number of minor versions a kubelet may be older than the API server.
+	MaxKubeletSkew int `json:"maxKubeletSkew"`
+}
+
+type CronjobsConfig struct {
+	// MaxMissedRuns is the number of scheduled runs a cronjob may miss before it is unhealthy.
+	MaxMissedRuns int `json:"maxMissedRuns"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:     metav1.Duration{Duration: time.Minute},
+		MinInterval: metav1.Duration{Duration: 30 * time.Second},
+		Checks:      map[string]CheckConfig{},
+		Ignore: []IgnoreRule{
+			{
+				Namespaces: []string{
+					"ci-*",
+					"*-ci-*",
+					"*-ci",
+					"lab-*",
+					"*-lab-*",
+					"*-lab",
+				},
+			},
+		},
+		Cassandra: CassandraConfig{
+			Namespace: "cassandra",
+			Pod:       "k8ssandra-dc1-default-sts-0",
+			Container: "cassandra",
+			Secret:    "k8ssandra-superuser",
+		},
+		RookCeph: RookCephConfig{
+			Namespace:     "rook-ceph",
+			ToolsSelector: "app=rook-ceph-tools",
+		},
+		Cronjobs: CronjobsConfig{
+			MaxMissedRuns: 100,
+		},
+		Pods: PodsConfig{
+			RestartWindow:          metav1.Duration{Duration: 15 * time.Minute},
+			MinRestarts:            3,
+			PendingGracePeriod:     metav1.Duration{Duration: 5 * time.Minute},
+			TerminatingGracePeriod: metav1.Duration{Duration: time.Minute},
+		},
+		Nodes: NodesConfig{
+			HeartbeatTimeout: metav1.Duration{Duration: 10 * time.Minute},
+			MaxKubeletSkew:   3,
+		},
+	}
+}
+
+// LoadConfig reads the config file at file on top of the defaults.
+// A missing file is only an error if required is set.
+func LoadConfig(file string, required bool) (Config, error) {
+	config := DefaultConfig()
+
+	content, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return config, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("read config file: %v", err)
+	}
+
+	err = yaml.UnmarshalStrict(content, &config)
+	if err != nil {
+		return Config{}, fmt.Errorf("parse config file %s: %v", file, err)
+	}
+
+	err = config.validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("validate config file %s: %v", file, err)
+	}
+
+	return config, nil
+}
+
+func (c Config) validate() error {
+	if c.Output != "" {
+		err := validateOutput(c.Output)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Timeout.Duration <= 0 {
+		return fmt.Errorf("timeout must be positive, got %v", c.Timeout.Duration)
+	}
+
+	if c.MinInterval.Duration <= 0 {
+		return fmt.Errorf("minInterval must be positive, got %v", c.MinInterval.Duration)
+	}
+
+	for name, check := range c.Checks {
+		if !isCheck(name) {
+			return fmt.Errorf("unknown check %q", name)
+		}
+
+		if check.Timeout != nil && check.Timeout.Duration <= 0 {
+			return fmt.Errorf("checks.%s.timeout must be positive, got %v", name, check.Timeout.Duration)
+		}
+	}
+
+	_, err := compileIgnoreRules(c.Ignore)
+	if err != nil {
+		return err
+	}
+
+	if c.Cronjobs.MaxMissedRuns < 1 {
+		return fmt.Errorf("cronjobs.maxMissedRuns must be positive, got %d", c.Cronjobs.MaxMissedRuns)
+	}
+
+	if c.Pods.RestartWindow.Duration <= 0 {
+		return fmt.Errorf("pods.restartWindow must be positive, got %v", c.Pods.RestartWindow.Duration)
+	}
+
+	if c.Pods.MinRestarts < 1 {
+		return fmt.Errorf("pods.minRestarts must be positive, got %d", c.Pods.MinRestarts)
+	}
+
+	if c.Pods.PendingGracePeriod.Duration < 0 {
+		return fmt.Errorf("pods.pendingGracePeriod must not be negative, got %v", c.Pods.PendingGracePeriod.Duration)
+	}
+
+	if c.Pods.TerminatingGracePeriod.Duration < 0 {
+		return fmt.Errorf("pods.terminatingGracePeriod must not be negative, got %v", c.Pods.TerminatingGracePeriod.Duration)
+	}
+
+	if c.Nodes.HeartbeatTimeout.Duration <= 0 {
+		return fmt.Errorf("nodes.heartbeatTimeout must be positive, got %v", c.Nodes.HeartbeatTimeout.Duration)
+	}
+
+	if c.Nodes.MaxKubeletSkew < 0 {
+		return fmt.Errorf("nodes.maxKubeletSkew must not be negative, got %d", c.Nodes.MaxKubeletSkew)
+	}
+
+	return nil
+}
+
+// enabled reports if a check is enabled by the config, checks are enabled unless disabled explicitly.
+func (c Config) enabled(name string) bool {
+	check, ok := c.Checks[name]
+	if !ok || check.Enabled == nil {
+		return true
+	}
+
+	return *check.Enabled
+}
+
+// timeout returns how long the check may take to evaluate.
+func (c Config) timeout(name string) time.Duration {
+	check, ok := c.Checks[name]
+	if !ok || check.Timeout == nil {
+		return c.Timeout.Duration
+	}
+
+	return check.Timeout.Duration
+}
@@ -0,0 +1,74 @@
+package k8status
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	merged := DefaultConfig()
+	merged.Output = OutputJSON
+	merged.Cronjobs.MaxMissedRuns = 5
+	merged.Pods.MinRestarts = 10
+
+	tests := []struct {
+		name     string
+		content  *string
+		required bool
+		want     Config
+		wantErr  string
+	}{
+		{name: "missing file", want: DefaultConfig()},
+		{name: "missing required file", required: true, wantErr: "read config file"},
+		{name: "empty file", content: ptr(""), want: DefaultConfig()},
+		{
+			name:    "merged with defaults",
+			content: ptr("output: json\ncronjobs:\n  maxMissedRuns: 5\npods:\n  minRestarts: 10\n"),
+			want:    merged,
+		},
+		{name: "unknown field", content: ptr("cronjob:\n  maxMissedRuns: 5\n"), wantErr: `unknown field "cronjob"`},
+		{name: "malformed", content: ptr("timeout: [1m\n"), wantErr: "parse config file"},
+		{name: "unknown output", content: ptr("output: xml\n"), wantErr: `unknown output format "xml"`},
+		{name: "timeout", content: ptr("timeout: 0s\n"), wantErr: "timeout must be positive"},
+		{name: "unknown check", content: ptr("checks:\n  node:\n    enabled: false\n"), wantErr: `unknown check "node"`},
+		{name: "check timeout", content: ptr("checks:\n  pods:\n    timeout: -1s\n"), wantErr: "checks.pods.timeout must be positive"},
+		{name: "max missed runs", content: ptr("cronjobs:\n  maxMissedRuns: 0\n"), wantErr: "cronjobs.maxMissedRuns must be positive"},
+		{name: "heartbeat timeout", content: ptr("nodes:\n  heartbeatTimeout: 0s\n"), wantErr: "nodes.heartbeatTimeout must be positive"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, fmt.Sprintf("config-%d.yaml", i))
+			if tt.content != nil {
+				err := os.WriteFile(file, []byte(*tt.content), 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadConfig(file, tt.required)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
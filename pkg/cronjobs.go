@@ -2,40 +2,50 @@ package k8status
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
 	"github.com/aptible/supercronic/cronexpr"
 	batchv1 "k8s.io/api/batch/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type cronjobsStatus struct {
+	config    *Config
+	policy    *policy
+	events    eventIndex
 	total     int
 	ignored   int
+	warnings  int
 	healthy   int
 	cronjobs  []batchv1.CronJob
 	unhealthy int
 }
 
-func NewCronjobsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	cronjobsList, err := client.clientset.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{})
+func NewCronjobsStatus(ctx context.Context, env *environment) (status, error) {
+	cronjobs, err := listInScope(env, func(namespace string) ([]batchv1.CronJob, error) {
+		return listCronJobs(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	cronjobs := cronjobsList.Items
-
 	status := &cronjobsStatus{
+		config:   env.config,
+		policy:   env.policy,
 		cronjobs: []batchv1.CronJob{},
 	}
 	status.add(cronjobs)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *cronjobsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d cronjobs are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d cronjobs are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *cronjobsStatus) Details(w io.Writer, colored bool) error {
@@ -43,22 +53,38 @@ func (s *cronjobsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *cronjobsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 52
+	if s.Severity() == SeverityCritical {
+		return exitCodeCronjobs
 	}
 
 	return 0
 }
 
+func (s *cronjobsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *cronjobsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *cronjobsStatus) toTable() Table {
-	header := []string{"Namespace", "Cronjob", "Status", "Last Success"}
+	header := []string{"Namespace", "Cronjob", "Status", "Last Success", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.cronjobs {
 		status := ""
 
-		if failed100times(item) {
-			status = "Too many missed start time (> 100)"
+		if missedTooManyRuns(item, s.config.Cronjobs.MaxMissedRuns) {
+			status = fmt.Sprintf("Too many missed start time (> %d)", s.config.Cronjobs.MaxMissedRuns)
 		}
 
 		lastSucessful := "Never"
@@ -66,13 +92,21 @@ func (s *cronjobsStatus) toTable() Table {
 			lastSucessful = item.Status.LastSuccessfulTime.String()
 		}
 
-		row := []string{item.Namespace, item.Name, status, lastSucessful}
+		row := []string{
+			item.Namespace,
+			item.Name,
+			status,
+			lastSucessful,
+			s.events.last(item.UID),
+		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -86,15 +120,18 @@ func (s *cronjobsStatus) add(cronjobs []batchv1.CronJob) {
 		}
 
 		// Health checking the job and pod (created by the cronjob) is skiped, because jobs and pods are checked separately.
-		healthy := !failed100times(item)
+		healthy := !missedTooManyRuns(item, s.config.Cronjobs.MaxMissedRuns)
 
 		if healthy {
 			s.healthy++
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.cronjobs = append(s.cronjobs, item)
@@ -102,20 +139,20 @@ func (s *cronjobsStatus) add(cronjobs []batchv1.CronJob) {
 	}
 }
 
-func failed100times(item batchv1.CronJob) bool {
+func missedTooManyRuns(item batchv1.CronJob, maxMissedRuns int) bool {
 	if item.Status.LastSuccessfulTime == nil && item.Status.LastScheduleTime == nil {
 		return false
 	}
 
-	// TODO: this early return avoids panicking with next100RunTimes when LastSuccessfulTime == nil
+	// TODO: this early return avoids panicking with nextRunTimes when LastSuccessfulTime == nil
 	//       and it passes the tests but is it the correct behaviour?
 	if item.Status.LastSuccessfulTime == nil {
 		return true
 	}
 
-	next100RunTimes := cronexpr.MustParse(item.Spec.Schedule).NextN(item.Status.LastSuccessfulTime.Time, 100)
-	the100ScheduleTime := next100RunTimes[len(next100RunTimes)-1]
-	failed100times := the100ScheduleTime.Before(time.Now())
+	nextRunTimes := cronexpr.MustParse(item.Spec.Schedule).NextN(item.Status.LastSuccessfulTime.Time, uint(maxMissedRuns))
+	lastScheduleTime := nextRunTimes[len(nextRunTimes)-1]
+	missedTooManyRuns := lastScheduleTime.Before(time.Now())
 
-	return failed100times
+	return missedTooManyRuns
 }
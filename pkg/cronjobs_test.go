@@ -1,6 +1,7 @@
 package k8status
 
 import (
+	"context"
 	"io"
 	"testing"
 	"time"
@@ -228,7 +229,15 @@ func Test_cronjobsStatus_ExitCode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			policy, err := newPolicy(context.Background(), nil, &config, []string{""})
+			if err != nil {
+				t.Fatalf("newPolicy() = %v, want %v", err, "success")
+			}
+
 			status := cronjobsStatus{
+				config:   &config,
+				policy:   policy,
 				cronjobs: []batchv1.CronJob{},
 			}
 			status.add(tt.cronjobs)
@@ -238,7 +247,7 @@ func Test_cronjobsStatus_ExitCode(t *testing.T) {
 				t.Errorf("printCronjobStatus() = %v, want %v", got, tt.want)
 			}
 
-			err := status.Details(io.Discard, false)
+			err = status.Details(io.Discard, false)
 			if err != nil {
 				t.Errorf("printCronjobStatusDetails() = %v, want %v", err, "success")
 			}
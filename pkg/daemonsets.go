@@ -6,35 +6,42 @@ import (
 	"io"
 
 	appsv1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type daemonsetsStatus struct {
+	policy     *policy
+	events     eventIndex
 	total      int
 	ignored    int
+	warnings   int
 	healthy    int
 	daemonSets []appsv1.DaemonSet
 	unhealthy  int
 }
 
-func NewDaemonsetsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	daemonsetsList, err := client.clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+func NewDaemonsetsStatus(ctx context.Context, env *environment) (status, error) {
+	daemonsets, err := listInScope(env, func(namespace string) ([]appsv1.DaemonSet, error) {
+		return listDaemonSets(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	daemonsets := daemonsetsList.Items
-
 	status := &daemonsetsStatus{
+		policy:     env.policy,
 		daemonSets: []appsv1.DaemonSet{},
 	}
 	status.add(daemonsets)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *daemonsetsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d daemonsets are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d daemonsets are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *daemonsetsStatus) Details(w io.Writer, colored bool) error {
@@ -42,17 +49,33 @@ func (s *daemonsetsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *daemonsetsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 51
+	if s.Severity() == SeverityCritical {
+		return exitCodeDaemonsets
 	}
 
 	return 0
 }
 
+func (s *daemonsetsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *daemonsetsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *daemonsetsStatus) toTable() Table {
-	header := []string{"Namespace", "Daemonset", "Scheduled", "Current", "Ready", "Up-to-date", "Available"}
+	header := []string{"Namespace", "Daemonset", "Scheduled", "Current", "Ready", "Up-to-date", "Available", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.daemonSets {
 		row := []string{
 			item.Namespace,
@@ -62,13 +85,16 @@ func (s *daemonsetsStatus) toTable() Table {
 			fmt.Sprintf("%d", item.Status.NumberReady),
 			fmt.Sprintf("%d", item.Status.UpdatedNumberScheduled),
 			fmt.Sprintf("%d", item.Status.NumberAvailable),
+			s.events.last(item.UID),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -81,8 +107,11 @@ func (s *daemonsetsStatus) add(pvcs []appsv1.DaemonSet) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.daemonSets = append(s.daemonSets, item)
@@ -6,35 +6,42 @@ import (
 	"io"
 
 	appsv1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type deploymentsStatus struct {
+	policy      *policy
+	events      eventIndex
 	total       int
 	ignored     int
+	warnings    int
 	healthy     int
 	deployments []appsv1.Deployment
 	unhealthy   int
 }
 
-func NewDeploymentsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	deploymentsList, err := client.clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+func NewDeploymentsStatus(ctx context.Context, env *environment) (status, error) {
+	deployments, err := listInScope(env, func(namespace string) ([]appsv1.Deployment, error) {
+		return listDeployments(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	deployments := deploymentsList.Items
-
 	status := &deploymentsStatus{
+		policy:      env.policy,
 		deployments: []appsv1.Deployment{},
 	}
 	status.add(deployments)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *deploymentsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d deployments are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d deployments are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *deploymentsStatus) Details(w io.Writer, colored bool) error {
@@ -42,17 +49,33 @@ func (s *deploymentsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *deploymentsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 48
+	if s.Severity() == SeverityCritical {
+		return exitCodeDeployments
 	}
 
 	return 0
 }
 
+func (s *deploymentsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *deploymentsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *deploymentsStatus) toTable() Table {
-	header := []string{"Namespace", "Deployment", "Replicas", "Available", "Up-to-date", "Ready"}
+	header := []string{"Namespace", "Deployment", "Replicas", "Available", "Up-to-date", "Ready", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.deployments {
 		row := []string{
 			item.Namespace,
@@ -61,13 +84,16 @@ func (s *deploymentsStatus) toTable() Table {
 			fmt.Sprintf("%d", item.Status.AvailableReplicas),
 			fmt.Sprintf("%d", item.Status.UpdatedReplicas),
 			fmt.Sprintf("%d", item.Status.ReadyReplicas),
+			s.events.last(item.UID),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -80,8 +106,11 @@ func (s *deploymentsStatus) add(deployments []appsv1.Deployment) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.deployments = append(s.deployments, item)
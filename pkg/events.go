@@ -0,0 +1,90 @@
+package k8status
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// maxEventMessage limits the length of event messages in detail tables.
+const maxEventMessage = 120
+
+// eventIndex describes the most recent Warning event of every involved object by its UID.
+// Objects recreated with the same name, e.g. the pods of statefulsets, do not inherit the events of their predecessor.
+type eventIndex map[types.UID]string
+
+// warningEvents loads the Warning events of the evaluated namespaces once per evaluation.
+// Events only explain failures, if they can not be loaded the index is empty and eventsErr explains why.
+func (env *environment) warningEvents(ctx context.Context) eventIndex {
+	env.eventsOnce.Do(func() {
+		events, err := listInScope(env, func(namespace string) ([]v1.Event, error) {
+			return listWarningEvents(ctx, env.client, namespace)
+		})
+		if err != nil {
+			env.events = eventIndex{}
+			env.eventsErr = fmt.Errorf("list warning events: %v", err)
+			return
+		}
+
+		env.events = newEventIndex(events, time.Now())
+	})
+
+	return env.events
+}
+
+func newEventIndex(events []v1.Event, now time.Time) eventIndex {
+	latest := map[types.UID]v1.Event{}
+
+	for _, event := range events {
+		key := event.InvolvedObject.UID
+		if key == "" {
+			continue
+		}
+
+		previous, ok := latest[key]
+		if !ok || eventTime(event).After(eventTime(previous)) {
+			latest[key] = event
+		}
+	}
+
+	index := eventIndex{}
+	for key, event := range latest {
+		index[key] = describeEvent(event, now)
+	}
+
+	return index
+}
+
+// last describes the most recent Warning event of an object, it is empty if there is none.
+func (index eventIndex) last(uid types.UID) string {
+	return index[uid]
+}
+
+func eventTime(event v1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func describeEvent(event v1.Event, now time.Time) string {
+	message := strings.TrimSpace(event.Message)
+	message, _, _ = strings.Cut(message, "\n")
+
+	if len([]rune(message)) > maxEventMessage {
+		message = string([]rune(message)[:maxEventMessage-3]) + "..."
+	}
+
+	return fmt.Sprintf("%s (%s ago): %s", event.Reason, duration.HumanDuration(now.Sub(eventTime(event))), message)
+}
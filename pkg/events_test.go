@@ -0,0 +1,49 @@
+package k8status
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_newEventIndex(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := func(uid types.UID, kind, name, reason, message string, age time.Duration) v1.Event {
+		return v1.Event{
+			InvolvedObject: v1.ObjectReference{Kind: kind, Namespace: "shop", Name: name, UID: uid},
+			Reason:         reason,
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	index := newEventIndex([]v1.Event{
+		event("uid-web-1", "Pod", "web-1", "FailedScheduling", "0/3 nodes are available", 10*time.Minute),
+		event("uid-web-1", "Pod", "web-1", "BackOff", "Back-off restarting failed container\nsecond line", 2*time.Minute),
+		event("uid-data", "PersistentVolumeClaim", "data", "FailedMount", "volume is already attached", 3*time.Hour),
+		event("uid-db-0-old", "Pod", "db-0", "FailedMount", "volume is already attached", time.Hour),
+		event("", "Pod", "api-1", "BackOff", "Back-off restarting failed container", time.Minute),
+	}, now)
+
+	tests := []struct {
+		name string
+		uid  types.UID
+		want string
+	}{
+		{name: "latest event", uid: "uid-web-1", want: "BackOff (2m ago): Back-off restarting failed container"},
+		{name: "claim", uid: "uid-data", want: "FailedMount (3h ago): volume is already attached"},
+		{name: "recreated pod", uid: "uid-db-0-new", want: ""},
+		{name: "no events", uid: "uid-api-1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := index.last(tt.uid)
+			if got != tt.want {
+				t.Errorf("eventIndex.last() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
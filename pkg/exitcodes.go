@@ -0,0 +1,165 @@
+package k8status
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Exit codes of the checks, each check has its own code.
+const (
+	exitCodeVolumes      = 42
+	exitCodeNodes        = 43
+	exitCodeVolumeClaims = 44
+	exitCodeNamespaces   = 45
+	exitCodeCassandra    = 46
+	exitCodeRookCeph     = 47
+	exitCodeDeployments  = 48
+	exitCodeDaemonsets   = 49
+	exitCodeStatefulsets = 50
+	exitCodeJobs         = 51
+	exitCodeCronjobs     = 52
+	exitCodePods         = 53
+)
+
+const (
+	// exitCodeUnknown is returned if a check could not be evaluated.
+	exitCodeUnknown = 1
+	// exitCodeWarning is returned if the worst outcome of all checks is a warning.
+	exitCodeWarning = 41
+)
+
+// Bits of the exit code in bitmask mode. Exit codes are limited to 0-255 and codes above 125 have
+// a special meaning in shells, so checks of the same area share a bit.
+const (
+	exitBitUnknown    = 1
+	exitBitWarning    = 2
+	exitBitNodes      = 4
+	exitBitStorage    = 8
+	exitBitNamespaces = 16
+	exitBitWorkloads  = 32
+	exitBitJobs       = 64
+)
+
+const (
+	// ExitCodeModeSeverity returns the exit code of the most severe outcome.
+	ExitCodeModeSeverity = "severity"
+	// ExitCodeModeBitmask combines the bits of all failed checks.
+	ExitCodeModeBitmask = "bitmask"
+)
+
+var ExitCodeModes = []string{ExitCodeModeSeverity, ExitCodeModeBitmask}
+
+func validateExitCodeMode(mode string) error {
+	if slices.Contains(ExitCodeModes, mode) {
+		return nil
+	}
+
+	return fmt.Errorf("unknown exit code mode %q, expected one of %v", mode, ExitCodeModes)
+}
+
+func (results results) exitCodeFor(mode string) int {
+	if mode == ExitCodeModeBitmask {
+		return results.ExitBitmask()
+	}
+
+	return results.ExitCode()
+}
+
+// ExitCode derives the process exit code from the worst severity of all checks.
+// Critical checks report their own exit code, the first one in check order wins.
+func (results results) ExitCode() int {
+	switch results.Severity() {
+	case SeverityCritical:
+		for _, result := range results {
+			if result.severity == SeverityCritical {
+				return result.exitCode
+			}
+		}
+	case SeverityUnknown:
+		return exitCodeUnknown
+	case SeverityWarning:
+		return exitCodeWarning
+	}
+
+	return 0
+}
+
+// ExitBitmask combines the bits of all checks which are not OK.
+func (results results) ExitBitmask() int {
+	exitCode := 0
+
+	for _, result := range results {
+		switch result.severity {
+		case SeverityWarning:
+			exitCode |= exitBitWarning
+		case SeverityUnknown:
+			exitCode |= exitBitUnknown
+		case SeverityCritical:
+			exitCode |= exitBit(result.name)
+		}
+	}
+
+	return exitCode
+}
+
+func exitBit(name string) int {
+	for _, check := range checks {
+		if check.name == name {
+			return check.exitBit
+		}
+	}
+
+	return exitBitUnknown
+}
+
+// PrintChecks writes a table of all available checks and their exit codes.
+func PrintChecks(w io.Writer, colored bool) error {
+	header := []string{"Check", "Exit Code", "Description"}
+
+	rows := [][]string{}
+	for _, check := range checks {
+		row := []string{check.name, fmt.Sprintf("%d", check.exitCode), check.description}
+		rows = append(rows, row)
+	}
+
+	return Table{
+		Header: header,
+		Rows:   rows,
+	}.Fprint(w, colored)
+}
+
+// PrintExitCodes explains the exit codes of both exit code modes.
+func PrintExitCodes(w io.Writer, colored bool) error {
+	_, err := fmt.Fprintf(w, `Exit code mode %q (default): the most severe outcome wins.
+Critical checks win over checks which could not be evaluated, which win over warnings.
+If several checks are critical, the first one in the order below wins.
+
+Exit code mode %q: the exit code is the sum of the bits of all checks which are not ok.
+Checks of the same area share a bit.
+`, ExitCodeModeSeverity, ExitCodeModeBitmask)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"0", "0", "all checks are ok"},
+		{fmt.Sprintf("%d", exitCodeUnknown), fmt.Sprintf("%d", exitBitUnknown), "not evaluated"},
+		{fmt.Sprintf("%d", exitCodeWarning), fmt.Sprintf("%d", exitBitWarning), "warning"},
+	}
+	for _, check := range checks {
+		row := []string{fmt.Sprintf("%d", check.exitCode), fmt.Sprintf("%d", check.exitBit), check.name + " critical"}
+		rows = append(rows, row)
+	}
+
+	err = Table{
+		Header: []string{"Exit Code", "Bit", "Meaning"},
+		Rows:   rows,
+	}.Fprint(w, colored)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, "\nWith --output nagios the nagios plugin exit codes are used: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN.")
+	return err
+}
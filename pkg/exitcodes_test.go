@@ -0,0 +1,61 @@
+package k8status
+
+import (
+	"testing"
+)
+
+func Test_checks_uniqueExitCodes(t *testing.T) {
+	seen := map[int]string{
+		exitCodeUnknown: "unknown",
+		exitCodeWarning: "warning",
+	}
+	for _, check := range checks {
+		if other, ok := seen[check.exitCode]; ok {
+			t.Errorf("check %s uses exit code %d of %s", check.name, check.exitCode, other)
+		}
+		seen[check.exitCode] = check.name
+	}
+}
+
+func Test_results_ExitBitmask(t *testing.T) {
+	tests := []struct {
+		name    string
+		results results
+		want    int
+	}{
+		{
+			name: "all checks ok",
+			results: results{
+				{name: "nodes", severity: SeverityOK},
+				{name: "pods", severity: SeverityOK},
+			},
+			want: 0,
+		},
+		{
+			name: "warnings and errors",
+			results: results{
+				{name: "nodes", severity: SeverityWarning},
+				{name: "pods", severity: SeverityUnknown},
+			},
+			want: exitBitWarning | exitBitUnknown,
+		},
+		{
+			name: "critical checks of different areas",
+			results: results{
+				{name: "nodes", severity: SeverityCritical},
+				{name: "deployments", severity: SeverityCritical},
+				{name: "pods", severity: SeverityCritical},
+				{name: "cronjobs", severity: SeverityWarning},
+			},
+			want: exitBitNodes | exitBitWorkloads | exitBitWarning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.results.ExitBitmask()
+			if got != tt.want {
+				t.Errorf("results.ExitBitmask() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,35 +6,42 @@ import (
 	"io"
 
 	v1 "k8s.io/api/batch/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type jobsStatus struct {
+	policy    *policy
+	events    eventIndex
 	total     int
 	ignored   int
+	warnings  int
 	healthy   int
 	jobs      []v1.Job
 	unhealthy int
 }
 
-func NewJobsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	jobsList, err := client.clientset.BatchV1().Jobs("").List(ctx, metav1.ListOptions{})
+func NewJobsStatus(ctx context.Context, env *environment) (status, error) {
+	jobs, err := listInScope(env, func(namespace string) ([]v1.Job, error) {
+		return listJobs(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	jobs := jobsList.Items
-
 	status := &jobsStatus{
-		jobs: []v1.Job{},
+		policy: env.policy,
+		jobs:   []v1.Job{},
 	}
 	status.add(jobs)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *jobsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d jobs are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d jobs are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *jobsStatus) Details(w io.Writer, colored bool) error {
@@ -42,17 +49,33 @@ func (s *jobsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *jobsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 51
+	if s.Severity() == SeverityCritical {
+		return exitCodeJobs
 	}
 
 	return 0
 }
 
+func (s *jobsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *jobsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *jobsStatus) toTable() Table {
-	header := []string{"Namespace", "Job", "Active", "Completions", "Succeeded", "Failed"}
+	header := []string{"Namespace", "Job", "Active", "Completions", "Succeeded", "Failed", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.jobs {
 		row := []string{
 			item.Namespace,
@@ -61,13 +84,16 @@ func (s *jobsStatus) toTable() Table {
 			fmt.Sprintf("%d", *item.Spec.Completions),
 			fmt.Sprintf("%d", item.Status.Succeeded),
 			fmt.Sprintf("%d", item.Status.Failed),
+			s.events.last(item.UID),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -80,8 +106,11 @@ func (s *jobsStatus) add(jobs []v1.Job) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.jobs = append(s.jobs, item)
@@ -0,0 +1,164 @@
+package k8status
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (results results) JUnit(w io.Writer, now time.Time) error {
+	suites := junitTestSuites{
+		Name: "k8status",
+	}
+
+	for _, result := range results {
+		suite := result.junitTestSuite(now)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+func (result *result) junitTestSuite(now time.Time) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      result.label(),
+		Timestamp: now.Format("2006-01-02T15:04:05"),
+		TestCases: []junitTestCase{},
+	}
+
+	if result.err != nil {
+		suite.Tests = 1
+		suite.Errors = 1
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      result.label(),
+			Classname: result.label(),
+			Error: &junitMessage{
+				Message: result.err.Error(),
+			},
+		})
+
+		return suite
+	}
+
+	details := result.report.Details
+
+	for i, row := range details.Rows {
+		testCase := junitTestCase{
+			Name:      junitTestCaseName(details.Header, row),
+			Classname: result.label(),
+		}
+
+		severity := SeverityCritical
+		if i < len(details.Severities) {
+			severity = details.Severities[i]
+		}
+
+		message := &junitMessage{
+			Message: strings.ToLower(rowSeverity(severity)),
+			Text:    junitDescribeRow(details.Header, row),
+		}
+
+		if severity == SeverityCritical {
+			testCase.Failure = message
+			suite.Failures++
+		} else {
+			testCase.Skipped = message
+			suite.Skipped++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	// rows are not always objects, e.g. ceph health checks, and may omit unhealthy objects
+	if result.report.Healthy > 0 || len(details.Rows) == 0 {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%d of %d healthy", result.report.Healthy, result.report.Total),
+			Classname: result.label(),
+		})
+	}
+
+	suite.Tests = len(suite.TestCases)
+
+	return suite
+}
+
+func junitTestCaseName(header []string, row []string) string {
+	if len(row) == 0 {
+		return ""
+	}
+
+	if len(header) > 1 && len(row) > 1 && header[0] == "Namespace" {
+		return row[0] + "/" + row[1]
+	}
+
+	return row[0]
+}
+
+func junitDescribeRow(header []string, row []string) string {
+	lines := []string{}
+
+	for i, value := range row {
+		name := fmt.Sprintf("%d", i)
+		if i < len(header) {
+			name = header[i]
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+	}
+
+	return strings.Join(lines, "\n")
+}
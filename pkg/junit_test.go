@@ -0,0 +1,79 @@
+package k8status
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_result_junitTestSuite(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	details := Table{
+		Header:     []string{"Namespace", "Pod", "Status"},
+		Rows:       [][]string{{"shop", "web-1", "CrashLoopBackOff"}, {"lab-1", "api-1", "Pending"}},
+		Severities: []Severity{SeverityCritical, SeverityOK},
+	}
+
+	tests := []struct {
+		name   string
+		result *result
+		want   junitTestSuite
+	}{
+		{
+			name:   "failure and skipped rows",
+			result: &result{name: "pods", report: report{Total: 5, Healthy: 3, Ignored: 1, Unhealthy: 2, Details: details}},
+			want: junitTestSuite{
+				Name: "pods", Tests: 3, Failures: 1, Skipped: 1, Timestamp: "2024-05-01T12:00:00",
+				TestCases: []junitTestCase{
+					{
+						Name: "shop/web-1", Classname: "pods",
+						Failure: &junitMessage{Message: "critical", Text: "Namespace: shop\nPod: web-1\nStatus: CrashLoopBackOff"},
+					},
+					{
+						Name: "lab-1/api-1", Classname: "pods",
+						Skipped: &junitMessage{Message: "ignored", Text: "Namespace: lab-1\nPod: api-1\nStatus: Pending"},
+					},
+					{Name: "3 of 5 healthy", Classname: "pods"},
+				},
+			},
+		},
+		{
+			name:   "no healthy objects",
+			result: &result{name: "pods", report: report{Total: 2, Unhealthy: 2, Details: Table{Header: details.Header, Rows: details.Rows[:1]}}},
+			want: junitTestSuite{
+				Name: "pods", Tests: 1, Failures: 1, Timestamp: "2024-05-01T12:00:00",
+				TestCases: []junitTestCase{
+					{
+						Name: "shop/web-1", Classname: "pods",
+						Failure: &junitMessage{Message: "critical", Text: "Namespace: shop\nPod: web-1\nStatus: CrashLoopBackOff"},
+					},
+				},
+			},
+		},
+		{
+			name:   "passed",
+			result: &result{name: "nodes", report: report{Total: 3, Healthy: 3}},
+			want: junitTestSuite{
+				Name: "nodes", Tests: 1, Timestamp: "2024-05-01T12:00:00",
+				TestCases: []junitTestCase{{Name: "3 of 3 healthy", Classname: "nodes"}},
+			},
+		},
+		{
+			name:   "error",
+			result: &result{name: "cassandra", err: errors.New("connection refused")},
+			want: junitTestSuite{
+				Name: "cassandra", Tests: 1, Errors: 1, Timestamp: "2024-05-01T12:00:00",
+				TestCases: []junitTestCase{{Name: "cassandra", Classname: "cassandra", Error: &junitMessage{Message: "connection refused"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.result.junitTestSuite(now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("junitTestSuite() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
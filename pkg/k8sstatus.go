@@ -3,77 +3,246 @@ package k8status
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/urfave/cli/v2"
 )
 
-type newStatus func(ctx context.Context, client *KubernetesClient) (status, error)
+type newStatus func(ctx context.Context, env *environment) (status, error)
+
+// environment is shared by all checks of one evaluation.
+type environment struct {
+	client *KubernetesClient
+	config *Config
+	policy *policy
+	// namespaces are evaluated by the namespaced checks, "" stands for all namespaces.
+	namespaces []string
+	// denied lists the cluster-scoped resources the identity may not list.
+	denied map[resource]bool
+	// checks lists the names of the selected checks.
+	checks map[string]bool
+
+	eventsOnce sync.Once
+	events     eventIndex
+	eventsErr  error
+	ownersOnce sync.Once
+	owners     ownerIndex
+}
 
 type status interface {
 	Summary(w io.Writer) error
 	Details(w io.Writer, colored bool) error
 	ExitCode() int
+	Severity() Severity
+	Report() report
 }
 
 type result struct {
-	name     string
-	summary  io.ReadWriter
-	details  io.ReadWriter
+	name string
+	// cluster is the name of the evaluated cluster in multi-cluster mode.
+	cluster  string
+	summary  *bytes.Buffer
+	details  *bytes.Buffer
 	exitCode int
+	severity Severity
+	report   report
 	err      error
 }
 
-type futures []<-chan *result
+// label names the result in reports, it includes the cluster in multi-cluster mode.
+func (result *result) label() string {
+	if result.cluster == "" {
+		return result.name
+	}
+
+	return result.cluster + "/" + result.name
+}
+
+type future struct {
+	name    string
+	ctx     context.Context
+	timeout time.Duration
+	result  <-chan *result
+}
+
+type futures []future
 
 type results []*result
 
-type check struct {
-	name   string
-	status newStatus
+// Options selects the checks to evaluate and configures how their results are printed.
+type Options struct {
+	Colored      bool
+	Output       string
+	ExitCodeMode string
+	Only         []string
+	Skip         []string
+	Config       Config
 }
 
-func Run(ctx context.Context, client *KubernetesClient, colored bool) error {
-	fmt.Println(time.Now().Format("2006-01-02 15:04:05"))
+func Run(ctx context.Context, client *KubernetesClient, options Options) error {
+	output := options.Output
 
-	checks := []check{
-		{name: "NewNodeStatus", status: NewNodeStatus},
-		{name: "NewCassandraStatus", status: NewCassandraStatus},
-		{name: "NewRookCephStatus", status: NewRookCephStatus},
-		{name: "NewVolumesStatus", status: NewVolumesStatus},
-		{name: "NewVolumeClaimsStatus", status: NewVolumeClaimsStatus},
-		{name: "NewNamespacesStatus", status: NewNamespacesStatus},
-		{name: "NewDaemonsetsStatus", status: NewDaemonsetsStatus},
-		{name: "NewStatefulsetsStatus", status: NewStatefulsetsStatus},
-		{name: "NewDeploymentsStatus", status: NewDeploymentsStatus},
-		{name: "NewCronjobsStatus", status: NewCronjobsStatus},
-		{name: "NewJobsStatus", status: NewJobsStatus},
-		{name: "NewPodsStatus", status: NewPodsStatus},
+	checks, exitCodeMode, err := options.prepare()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if output == OutputText {
+		fmt.Println(now.Format("2006-01-02 15:04:05"))
 	}
 
+	results := runChecks(ctx, client, checks, &options.Config, options.Colored)
+
+	err = results.Print(os.Stdout, output, exitCodeMode, now)
+	if err != nil {
+		return err
+	}
+
+	return exit(results, output, exitCodeMode)
+}
+
+// prepare validates the options and selects the checks to evaluate.
+func (options Options) prepare() ([]check, string, error) {
+	err := validateOutput(options.Output)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exitCodeMode := options.ExitCodeMode
+	if exitCodeMode == "" {
+		exitCodeMode = ExitCodeModeSeverity
+	}
+
+	err = validateExitCodeMode(exitCodeMode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	checks, err := selectChecks(options.Only, options.Skip, options.Config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return checks, exitCodeMode, nil
+}
+
+// exit derives the process exit code from the printed results.
+func exit(results results, output, exitCodeMode string) error {
+	if output == OutputNagios {
+		return cli.Exit("", nagiosExitCode(results.Severity()))
+	}
+
+	exitCode := results.exitCodeFor(exitCodeMode)
+	if exitCode != 0 {
+		if output == OutputText {
+			fmt.Println()
+		}
+		return cli.Exit("an issue was found", exitCode)
+	}
+
+	return nil
+}
+
+// changeChecks selects the checks evaluated on changes of the cluster, polled checks are left out.
+func changeChecks(checks []check) []check {
+	selected := []check{}
+
+	for _, check := range checks {
+		if !check.polled {
+			selected = append(selected, check)
+		}
+	}
+
+	return selected
+}
+
+// keep completes the results with the previous results of the checks which were not evaluated.
+func (evaluated results) keep(previous results, checks []check) results {
+	merged := results{}
+
+	for _, check := range checks {
+		result := evaluated.find(check.name)
+		if result == nil {
+			result = previous.find(check.name)
+		}
+
+		if result != nil {
+			merged = append(merged, result)
+		}
+	}
+
+	return merged
+}
+
+func runChecks(ctx context.Context, client *KubernetesClient, checks []check, config *Config, colored bool) results {
 	futures := futures{}
 
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout.Duration)
+	defer cancel()
+
+	env, err := newEnvironment(ctx, client, config, checks)
+	if err != nil {
+		for _, check := range checks {
+			ch := make(chan *result, 1)
+			ch <- &result{
+				name:     check.name,
+				exitCode: exitCodeUnknown,
+				severity: SeverityUnknown,
+				err:      err,
+			}
+			futures = append(futures, future{name: check.name, ctx: ctx, result: ch})
+		}
+
+		return futures.Await()
+	}
+
 	for _, check := range checks {
-		future := make(chan *result)
-		futures = append(futures, future)
+		// the overall timeout applies to every check as well
+		timeout := min(config.timeout(check.name), config.Timeout.Duration)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		// buffered, a check finishing after its timeout must not block forever
+		ch := make(chan *result, 1)
+		futures = append(futures, future{name: check.name, ctx: ctx, timeout: timeout, result: ch})
+
+		newCheck := check.status
+		skipped, denied := env.insufficientPermissions(check)
+		if denied {
+			newCheck = func(context.Context, *environment) (status, error) {
+				return skipped, nil
+			}
+		}
+
+		namespaced := check.namespaced()
+		readsEvents := slices.Contains(check.resources, resourceEvents)
 
 		go func(future chan *result, name string, newCheck newStatus) {
 			result := &result{
 				name: name,
 			}
 
-			check, err := newCheck(ctx, client)
+			check, err := newCheck(ctx, env)
 			if err != nil {
 				result.err = err
-				result.exitCode = 1
+				result.exitCode = exitCodeUnknown
+				result.severity = SeverityUnknown
 				future <- result
 				return
 			}
 
 			result.exitCode = check.ExitCode()
+			result.severity = check.Severity()
+			result.report = check.Report()
 
 			result.summary = &bytes.Buffer{}
 			err = check.Summary(result.summary)
@@ -83,6 +252,14 @@ func Run(ctx context.Context, client *KubernetesClient, colored bool) error {
 				return
 			}
 
+			if namespaced {
+				result.summary.WriteString(env.partialScope())
+			}
+
+			if result.report.Unhealthy > 0 {
+				result.summary.WriteString(env.policy.unapplied())
+			}
+
 			result.details = &bytes.Buffer{}
 			err = check.Details(result.details, colored)
 			if err != nil {
@@ -91,53 +268,109 @@ func Run(ctx context.Context, client *KubernetesClient, colored bool) error {
 				return
 			}
 
+			// unhealthy objects of checks reading events loaded them before
+			if readsEvents && result.report.Unhealthy > 0 && env.eventsErr != nil {
+				fmt.Fprintf(result.details, "The Last Event column is empty: %v\n", env.eventsErr)
+			}
+
 			future <- result
-		}(future, check.name, check.status)
+		}(ch, check.name, newCheck)
 	}
 
-	results := futures.Await()
+	return futures.Await()
+}
 
-	err := results.Errors(os.Stdout)
+func newEnvironment(ctx context.Context, client *KubernetesClient, config *Config, checks []check) (*environment, error) {
+	namespaces, err := resolveScope(ctx, client, checks)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = results.Summaries(os.Stdout)
+	policy, err := newPolicy(ctx, client, config, namespaces)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = results.Details(os.Stdout)
+	denied, err := deniedResources(ctx, client, checks)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	exitCode := results.ExitCode()
-	if exitCode != 0 {
-		fmt.Println()
-		return cli.Exit("an issue was found", exitCode)
+	selected := map[string]bool{}
+	for _, check := range checks {
+		selected[check.name] = true
 	}
 
-	return nil
+	return &environment{
+		client:     client,
+		config:     config,
+		policy:     policy,
+		namespaces: namespaces,
+		denied:     denied,
+		checks:     selected,
+	}, nil
 }
 
+// Await collects the results of all checks. A check which does not finish before its context is done
+// is reported as timed out.
 func (futures futures) Await() results {
 	results := results{}
 
 	for _, future := range futures {
-		results = append(results, <-future)
+		results = append(results, future.await())
 	}
 
 	return results
 }
 
+func (future future) await() *result {
+	select {
+	case result := <-future.result:
+		return result
+	case <-future.ctx.Done():
+	}
+
+	// prefer a result which was delivered right before the deadline
+	select {
+	case result := <-future.result:
+		return result
+	default:
+	}
+
+	err := future.ctx.Err()
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %v", future.timeout)
+	}
+
+	return &result{
+		name:     future.name,
+		exitCode: exitCodeUnknown,
+		severity: SeverityUnknown,
+		err:      err,
+	}
+}
+
+func (results results) Text(w io.Writer) error {
+	err := results.Errors(w)
+	if err != nil {
+		return err
+	}
+
+	err = results.Summaries(w)
+	if err != nil {
+		return err
+	}
+
+	return results.Details(w)
+}
+
 func (results results) Errors(w io.Writer) error {
 	for _, result := range results {
 		if result.err == nil {
 			continue
 		}
 
-		_, err := fmt.Fprintf(w, "%v error: %v\n", result.name, result.err)
+		_, err := fmt.Fprintf(w, "%v error: %v\n", result.label(), result.err)
 		if err != nil {
 			return err
 		}
@@ -152,22 +385,37 @@ func (results results) Summaries(w io.Writer) error {
 			continue
 		}
 
-		_, err := io.Copy(w, result.summary)
-		if err != nil {
-			return err
+		for i, line := range strings.SplitAfter(result.summary.String(), "\n") {
+			if line == "" {
+				continue
+			}
+
+			_, err := io.WriteString(w, summaryPrefix(result.severity, i)+line)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// summaryPrefix labels the first line of a summary with the severity and indents the following lines.
+func summaryPrefix(severity Severity, line int) string {
+	if line == 0 {
+		return fmt.Sprintf("%-10s ", "["+severity.String()+"]")
+	}
+
+	return strings.Repeat(" ", 11)
+}
+
 func (results results) Details(w io.Writer) error {
 	for _, result := range results {
 		if result.details == nil {
 			continue
 		}
 
-		_, err := io.Copy(w, result.details)
+		_, err := w.Write(result.details.Bytes())
 		if err != nil {
 			return err
 		}
@@ -176,12 +424,12 @@ func (results results) Details(w io.Writer) error {
 	return nil
 }
 
-func (results results) ExitCode() int {
+func (results results) Severity() Severity {
+	severities := []Severity{}
+
 	for _, result := range results {
-		if result.exitCode != 0 {
-			return result.exitCode
-		}
+		severities = append(severities, result.severity)
 	}
 
-	return 0
+	return worstSeverity(severities...)
 }
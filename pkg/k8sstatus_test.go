@@ -0,0 +1,79 @@
+package k8status
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type healthyStatus struct{}
+
+func (healthyStatus) Summary(w io.Writer) error               { return nil }
+func (healthyStatus) Details(w io.Writer, colored bool) error { return nil }
+func (healthyStatus) ExitCode() int                           { return 0 }
+func (healthyStatus) Severity() Severity                      { return SeverityOK }
+func (healthyStatus) Report() report                          { return report{} }
+
+func Test_runChecks_timeout(t *testing.T) {
+	config := DefaultConfig()
+	config.Checks["pods"] = CheckConfig{Timeout: &metav1.Duration{Duration: 10 * time.Millisecond}}
+
+	checks := []check{
+		{
+			name: "nodes",
+			status: func(ctx context.Context, env *environment) (status, error) {
+				return healthyStatus{}, nil
+			},
+		},
+		{
+			name: "pods",
+			status: func(ctx context.Context, env *environment) (status, error) {
+				// ignores ctx like a hanging exec
+				time.Sleep(time.Second)
+				return healthyStatus{}, nil
+			},
+		},
+	}
+
+	start := time.Now()
+	results := runChecks(context.Background(), &KubernetesClient{}, checks, &config, false)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("runChecks() took %v, expected the hanging check to time out", elapsed)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("runChecks() returned %d results, want 2", len(results))
+	}
+
+	if results[0].err != nil || results[0].severity != SeverityOK {
+		t.Errorf("runChecks() nodes = %v, %v, want no error and OK", results[0].err, results[0].severity)
+	}
+
+	if results[1].err == nil || results[1].err.Error() != "timed out after 10ms" {
+		t.Errorf("runChecks() pods error = %v, want timed out after 10ms", results[1].err)
+	}
+
+	if results[1].severity != SeverityUnknown || results[1].exitCode != exitCodeUnknown {
+		t.Errorf("runChecks() pods = %v, %d, want UNKNOWN, %d", results[1].severity, results[1].exitCode, exitCodeUnknown)
+	}
+}
+
+func Test_results_keep(t *testing.T) {
+	checks := []check{{name: "nodes"}, {name: "cassandra", polled: true}, {name: "pods"}}
+	previous := results{{name: "nodes"}, {name: "cassandra", severity: SeverityCritical}, {name: "pods"}}
+	evaluated := results{{name: "nodes", severity: SeverityWarning}, {name: "pods", severity: SeverityOK}}
+
+	got := evaluated.keep(previous, checks)
+	want := results{evaluated[0], previous[1], evaluated[1]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("keep() = %v, want %v", got, want)
+	}
+
+	if selected := changeChecks(checks); len(selected) != 2 || selected[1].name != "pods" {
+		t.Errorf("changeChecks() = %v, want nodes and pods", selected)
+	}
+}
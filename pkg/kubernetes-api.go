@@ -1,21 +1,32 @@
 package k8status
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
-func namespaceExists(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (bool, error) {
-	_, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+func namespaceExists(ctx context.Context, client *KubernetesClient, namespace string) (bool, error) {
+	var err error
+	if client.cached(resourceNamespaces) {
+		_, err = client.cache.factory.Core().V1().Namespaces().Lister().Get(namespace)
+	} else {
+		_, err = client.clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	}
 
 	if err == nil {
 		return true, nil
@@ -28,13 +39,13 @@ func namespaceExists(ctx context.Context, clientset *kubernetes.Clientset, names
 	return false, err
 }
 
-func listPods(
-	ctx context.Context,
-	clientset *kubernetes.Clientset,
-	namespace string,
-	listOptions metav1.ListOptions,
-) ([]v1.Pod, error) {
-	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, listOptions)
+func listPods(ctx context.Context, client *KubernetesClient, namespace string, selector labels.Selector) ([]v1.Pod, error) {
+	if client.cached(resourcePods) {
+		pods, err := client.cache.factory.Core().V1().Pods().Lister().Pods(namespace).List(selector)
+		return values(pods), err
+	}
+
+	pods, err := client.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +53,211 @@ func listPods(
 	return pods.Items, nil
 }
 
+// serverVersion reads the version of the API server.
+func serverVersion(client *KubernetesClient) (*version.Version, error) {
+	info, err := client.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("read server version: %v", err)
+	}
+
+	return version.ParseGeneric(info.GitVersion)
+}
+
+func listNodes(ctx context.Context, client *KubernetesClient) ([]v1.Node, error) {
+	if client.cached(resourceNodes) {
+		nodes, err := client.cache.factory.Core().V1().Nodes().Lister().List(labels.Everything())
+		return values(nodes), err
+	}
+
+	nodes, err := client.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return nodes.Items, nil
+}
+
+func listNamespaces(ctx context.Context, client *KubernetesClient) ([]v1.Namespace, error) {
+	if client.cached(resourceNamespaces) {
+		namespaces, err := client.cache.factory.Core().V1().Namespaces().Lister().List(labels.Everything())
+		return values(namespaces), err
+	}
+
+	namespaces, err := client.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaces.Items, nil
+}
+
+func getNamespace(ctx context.Context, client *KubernetesClient, name string) (*v1.Namespace, error) {
+	if client.cached(resourceNamespaces) {
+		return client.cache.factory.Core().V1().Namespaces().Lister().Get(name)
+	}
+
+	return client.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+}
+
+func listPersistentVolumes(ctx context.Context, client *KubernetesClient) ([]v1.PersistentVolume, error) {
+	if client.cached(resourcePersistentVolumes) {
+		volumes, err := client.cache.factory.Core().V1().PersistentVolumes().Lister().List(labels.Everything())
+		return values(volumes), err
+	}
+
+	volumes, err := client.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes.Items, nil
+}
+
+func listPersistentVolumeClaims(ctx context.Context, client *KubernetesClient, namespace string) ([]v1.PersistentVolumeClaim, error) {
+	if client.cached(resourcePersistentVolumeClaims) {
+		claims, err := client.cache.factory.Core().V1().PersistentVolumeClaims().Lister().PersistentVolumeClaims(namespace).List(labels.Everything())
+		return values(claims), err
+	}
+
+	claims, err := client.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return claims.Items, nil
+}
+
+func listDeployments(ctx context.Context, client *KubernetesClient, namespace string) ([]appsv1.Deployment, error) {
+	if client.cached(resourceDeployments) {
+		deployments, err := client.cache.factory.Apps().V1().Deployments().Lister().Deployments(namespace).List(labels.Everything())
+		return values(deployments), err
+	}
+
+	deployments, err := client.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return deployments.Items, nil
+}
+
+func listStatefulSets(ctx context.Context, client *KubernetesClient, namespace string) ([]appsv1.StatefulSet, error) {
+	if client.cached(resourceStatefulSets) {
+		statefulsets, err := client.cache.factory.Apps().V1().StatefulSets().Lister().StatefulSets(namespace).List(labels.Everything())
+		return values(statefulsets), err
+	}
+
+	statefulsets, err := client.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return statefulsets.Items, nil
+}
+
+func listDaemonSets(ctx context.Context, client *KubernetesClient, namespace string) ([]appsv1.DaemonSet, error) {
+	if client.cached(resourceDaemonSets) {
+		daemonsets, err := client.cache.factory.Apps().V1().DaemonSets().Lister().DaemonSets(namespace).List(labels.Everything())
+		return values(daemonsets), err
+	}
+
+	daemonsets, err := client.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return daemonsets.Items, nil
+}
+
+func listReplicaSets(ctx context.Context, client *KubernetesClient, namespace string) ([]appsv1.ReplicaSet, error) {
+	if client.cached(resourceReplicaSets) {
+		replicasets, err := client.cache.factory.Apps().V1().ReplicaSets().Lister().ReplicaSets(namespace).List(labels.Everything())
+		return values(replicasets), err
+	}
+
+	replicasets, err := client.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return replicasets.Items, nil
+}
+
+func listJobs(ctx context.Context, client *KubernetesClient, namespace string) ([]batchv1.Job, error) {
+	if client.cached(resourceJobs) {
+		jobs, err := client.cache.factory.Batch().V1().Jobs().Lister().Jobs(namespace).List(labels.Everything())
+		return values(jobs), err
+	}
+
+	jobs, err := client.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs.Items, nil
+}
+
+func listCronJobs(ctx context.Context, client *KubernetesClient, namespace string) ([]batchv1.CronJob, error) {
+	if client.cached(resourceCronJobs) {
+		cronjobs, err := client.cache.factory.Batch().V1().CronJobs().Lister().CronJobs(namespace).List(labels.Everything())
+		return values(cronjobs), err
+	}
+
+	cronjobs, err := client.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return cronjobs.Items, nil
+}
+
+// listWarningEvents lists the Warning events of a namespace, watch and serve read them from their cache.
+func listWarningEvents(ctx context.Context, client *KubernetesClient, namespace string) ([]v1.Event, error) {
+	if client.cached(resourceEvents) {
+		// the informer only caches Warning events
+		events, err := client.cache.events.Core().V1().Events().Lister().Events(namespace).List(labels.Everything())
+		return values(events), err
+	}
+
+	events, err := client.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: "type=" + v1.EventTypeWarning})
+	if err != nil {
+		return nil, err
+	}
+
+	return events.Items, nil
+}
+
+// execError is returned if a command executed in a pod fails.
+type execError struct {
+	// status is the exit status of the command, it is -1 if the command could not be run.
+	status int
+	stderr string
+	err    error
+}
+
+func (e *execError) Error() string {
+	message := e.err.Error()
+	if e.status >= 0 {
+		message = fmt.Sprintf("command exited with status %d", e.status)
+	}
+
+	stderr := strings.TrimSpace(e.stderr)
+	if stderr == "" {
+		return message
+	}
+
+	return fmt.Sprintf("%s: %s", message, stderr)
+}
+
+func (e *execError) Unwrap() error {
+	return e.err
+}
+
+// exec runs a shell command in a container and writes its output to stdout.
+// The command is stopped when ctx is done. If the command fails, an *execError carries
+// its exit status and stderr.
 func exec(
+	ctx context.Context,
 	client *KubernetesClient,
 	namespace string,
 	pod string,
@@ -63,7 +278,7 @@ func exec(
 			Stdin:     false,
 			Stdout:    true,
 			Stderr:    true,
-			TTY:       true,
+			TTY:       false,
 			Container: container,
 		}, scheme.ParameterCodec)
 
@@ -72,12 +287,23 @@ func exec(
 		return err
 	}
 
-	err = exec.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+	stderr := &bytes.Buffer{}
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
 		Stdout: stdout,
-		Stderr: os.Stderr,
+		Stderr: stderr,
 	})
 	if err != nil {
-		return err
+		status := -1
+		exitErr, ok := err.(utilexec.ExitError)
+		if ok {
+			status = exitErr.ExitStatus()
+		}
+
+		return &execError{
+			status: status,
+			stderr: stderr.String(),
+			err:    err,
+		}
 	}
 
 	return nil
@@ -0,0 +1,50 @@
+package k8status
+
+import (
+	"errors"
+	"testing"
+
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func Test_execError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *execError
+		want string
+	}{
+		{
+			name: "exit status and stderr",
+			err: &execError{
+				status: 2,
+				stderr: "nodetool: connection refused\n",
+				err:    utilexec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2},
+			},
+			want: "command exited with status 2: nodetool: connection refused",
+		},
+		{
+			name: "exit status without stderr",
+			err: &execError{
+				status: 1,
+				err:    utilexec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1},
+			},
+			want: "command exited with status 1",
+		},
+		{
+			name: "command could not be run",
+			err: &execError{
+				status: -1,
+				err:    errors.New("context canceled"),
+			},
+			want: "context canceled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.err.Error()
+			if got != tt.want {
+				t.Errorf("execError.Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package k8status
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// nagiosExitCode maps a severity to the exit codes of the nagios plugin API.
+func nagiosExitCode(severity Severity) int {
+	switch severity {
+	case SeverityOK:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Nagios prints a single line in the nagios plugin format with perfdata of every check.
+func (results results) Nagios(w io.Writer) error {
+	problems := []string{}
+	perfdata := []string{}
+
+	for _, result := range results {
+		label := strings.NewReplacer("-", "_", "/", "_").Replace(result.label())
+
+		if result.err != nil {
+			problems = append(problems, fmt.Sprintf("%s error: %v", result.label(), result.err))
+			continue
+		}
+
+		unhealthy := result.report.Unhealthy - result.report.Ignored
+		if result.severity != SeverityOK {
+			problems = append(problems, fmt.Sprintf("%d of %d %s unhealthy", unhealthy, result.report.Total, result.label()))
+		}
+
+		perfdata = append(perfdata,
+			fmt.Sprintf("%s_healthy=%d;;;", label, result.report.Healthy),
+			fmt.Sprintf("%s_unhealthy=%d;;;", label, unhealthy),
+		)
+	}
+
+	message := fmt.Sprintf("%d of %d checks are healthy", len(results), len(results))
+	if len(problems) != 0 {
+		message = strings.Join(problems, "; ")
+	}
+
+	// the pipe separates perfdata, it must not be part of the message
+	message = strings.ReplaceAll(strings.ReplaceAll(message, "|", "/"), "\n", " ")
+
+	_, err := fmt.Fprintf(w, "K8STATUS %s - %s | %s\n", results.Severity(), message, strings.Join(perfdata, " "))
+	return err
+}
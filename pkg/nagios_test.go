@@ -0,0 +1,52 @@
+package k8status
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_results_Nagios(t *testing.T) {
+	tests := []struct {
+		name    string
+		results results
+		want    string
+	}{
+		{
+			name: "ok",
+			results: results{
+				{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}},
+				{name: "volume-claims", severity: SeverityOK, report: report{Total: 2, Healthy: 2}},
+			},
+			want: "K8STATUS OK - 2 of 2 checks are healthy | nodes_healthy=3;;; nodes_unhealthy=0;;; volume_claims_healthy=2;;; volume_claims_unhealthy=0;;;\n",
+		},
+		{
+			name: "problems",
+			results: results{
+				{name: "pods", severity: SeverityCritical, report: report{Total: 10, Healthy: 7, Ignored: 1, Unhealthy: 3}},
+				{name: "cassandra", severity: SeverityUnknown, err: errors.New("nodetool failed | exit 1\nConnection refused")},
+			},
+			want: "K8STATUS CRITICAL - 2 of 10 pods unhealthy; cassandra error: nodetool failed / exit 1 Connection refused | pods_healthy=7;;; pods_unhealthy=2;;;\n",
+		},
+		{
+			name: "clusters",
+			results: results{
+				{name: "nodes", cluster: "prod", severity: SeverityWarning, report: report{Total: 3, Healthy: 2, Warnings: 1, Unhealthy: 1}},
+			},
+			want: "K8STATUS WARNING - 1 of 3 prod/nodes unhealthy | prod_nodes_healthy=2;;; prod_nodes_unhealthy=1;;;\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := tt.results.Nagios(w)
+			if err != nil {
+				t.Fatalf("Nagios() error = %v", err)
+			}
+
+			if w.String() != tt.want {
+				t.Errorf("Nagios() =\n%q\nwant\n%q", w.String(), tt.want)
+			}
+		})
+	}
+}
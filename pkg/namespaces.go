@@ -3,29 +3,33 @@ package k8status
 import (
 	"context"
 	"io"
-	"strings"
+	"slices"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type namespacesStatus struct {
+	policy     *policy
 	total      int
 	ignored    int
+	warnings   int
 	healthy    int
 	namespaces []v1.Namespace
 	unhealthy  int
 }
 
-func NewNamespacesStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	namespacesList, err := client.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+func NewNamespacesStatus(ctx context.Context, env *environment) (status, error) {
+	namespaces, err := listNamespaces(ctx, env.client)
 	if err != nil {
 		return nil, err
 	}
 
-	namespaces := namespacesList.Items
+	namespaces = slices.DeleteFunc(namespaces, func(namespace v1.Namespace) bool {
+		return !env.inScope(namespace.Name)
+	})
 
 	status := &namespacesStatus{
+		policy:     env.policy,
 		namespaces: []v1.Namespace{},
 	}
 	status.add(namespaces)
@@ -34,7 +38,7 @@ func NewNamespacesStatus(ctx context.Context, client *KubernetesClient) (status,
 }
 
 func (s *namespacesStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d namespaces are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d namespaces are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *namespacesStatus) Details(w io.Writer, colored bool) error {
@@ -42,28 +46,46 @@ func (s *namespacesStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *namespacesStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 43
+	if s.Severity() == SeverityCritical {
+		return exitCodeNamespaces
 	}
 
 	return 0
 }
 
+func (s *namespacesStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *namespacesStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *namespacesStatus) toTable() Table {
 	header := []string{"Namespace", "Phase"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.namespaces {
 		row := []string{
 			item.Name,
 			string(item.Status.Phase),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Name).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -76,8 +98,11 @@ func (s *namespacesStatus) add(namespaces []v1.Namespace) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Name) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.namespaces = append(s.namespaces, item)
@@ -88,12 +113,3 @@ func (s *namespacesStatus) add(namespaces []v1.Namespace) {
 func namespaceIsHealthy(item v1.Namespace) bool {
 	return item.Status.Phase == v1.NamespaceActive
 }
-
-func isCiOrLabNamespace(namespace string) bool {
-	return strings.HasPrefix(namespace, "ci-") ||
-		strings.Contains(namespace, "-ci-") ||
-		strings.HasSuffix(namespace, "-ci") ||
-		strings.HasPrefix(namespace, "lab-") ||
-		strings.Contains(namespace, "-lab-") ||
-		strings.HasSuffix(namespace, "-lab")
-}
@@ -4,36 +4,78 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/version"
 )
 
+// maintenanceAnnotation marks a node in planned maintenance, its value is "true" or the RFC 3339 time the maintenance ends.
+const maintenanceAnnotation = "k8status.io/maintenance"
+
+// nodePressureConditions are node conditions which are unhealthy when they are True.
+var nodePressureConditions = []v1.NodeConditionType{
+	v1.NodeMemoryPressure,
+	v1.NodeDiskPressure,
+	v1.NodePIDPressure,
+	v1.NodeNetworkUnavailable,
+}
+
+// nodeLifecycleTaints are set by the node controller on nodes which are not ready, they are reported by the Ready condition.
+var nodeLifecycleTaints = []string{v1.TaintNodeNotReady, v1.TaintNodeUnreachable}
+
 type nodesStatus struct {
-	total     int
-	healthy   int
-	nodes     []v1.Node
-	unhealthy int
+	config *Config
+	policy *policy
+	now    time.Time
+	// serverVersion is nil if the version of the API server can not be read.
+	serverVersion *version.Version
+	total         int
+	ignored       int
+	warnings      int
+	healthy       int
+	nodes         []v1.Node
+	unhealthy     int
+	maintenance   int
 }
 
-func NewNodeStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	nodesList, err := client.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+func NewNodeStatus(ctx context.Context, env *environment) (status, error) {
+	nodes, err := listNodes(ctx, env.client)
 	if err != nil {
 		return &nodesStatus{}, err
 	}
 
-	nodes := nodesList.Items
+	// the version skew is not checked if the version is unknown
+	serverVersion, _ := serverVersion(env.client)
 
-	status := &nodesStatus{}
+	status := &nodesStatus{
+		config:        env.config,
+		policy:        env.policy,
+		now:           time.Now(),
+		serverVersion: serverVersion,
+	}
 	status.add(nodes)
 
 	return status, nil
 }
 
 func (s *nodesStatus) Summary(w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%d of %d nodes are up and healthy.\n", s.healthy, s.total)
-	return err
+	err := printSummary(w, "%d of %d nodes are up and healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
+	if err != nil {
+		return err
+	}
+
+	if s.maintenance > 0 {
+		_, err := fmt.Fprintf(w, "- %d in maintenance\n", s.maintenance)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (s *nodesStatus) Details(w io.Writer, colored bool) error {
@@ -41,45 +83,246 @@ func (s *nodesStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *nodesStatus) ExitCode() int {
-	if s.unhealthy > 0 {
-		return 43
+	if s.Severity() == SeverityCritical {
+		return exitCodeNodes
 	}
 
 	return 0
 }
 
+func (s *nodesStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *nodesStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *nodesStatus) toTable() Table {
-	header := []string{"Node", "Status", "Messages"}
+	header := []string{"Node", "Status"}
+	for _, condition := range nodePressureConditions {
+		header = append(header, string(condition))
+	}
+	header = append(header, "NoExecute Taints", "Kubelet", "Last Heartbeat", "Issues", "Messages")
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, node := range s.nodes {
 		isReady, cordoned, messages := getNodeConditions(node)
-		row := []string{node.Name, formatStatus(isReady, cordoned), strings.Join(messages, "; ")}
+		failures, warnings := s.issues(node)
+
+		row := []string{node.Name, formatStatus(isReady, cordoned)}
+		for _, condition := range nodePressureConditions {
+			row = append(row, conditionStatus(node, condition))
+		}
+		row = append(row,
+			strings.Join(noExecuteTaints(node), ", "),
+			node.Status.NodeInfo.KubeletVersion,
+			formatHeartbeat(node, s.now),
+			strings.Join(append(failures, warnings...), "; "),
+			strings.Join(messages, "; "),
+		)
 		rows = append(rows, row)
+		severities = append(severities, s.impact(node).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
+
 func (s *nodesStatus) add(nodes []v1.Node) {
 	s.total += len(nodes)
 
 	for _, item := range nodes {
 		isReady, cordoned, _ := getNodeConditions(item)
+		failures, warnings := s.issues(item)
 
-		if nodeIsHealthy(isReady, cordoned) {
+		if nodeIsHealthy(isReady, cordoned) && len(failures) == 0 && len(warnings) == 0 {
 			s.healthy++
 			continue
 		}
 
-		s.nodes = append(s.nodes, item)
+		switch s.impact(item) {
+		case impactIgnored:
+			s.ignored++
+		case impactWarning:
+			s.warnings++
+		}
+
+		if inMaintenance, _ := nodeMaintenance(item, s.now); inMaintenance {
+			s.maintenance++
+		}
 
+		s.nodes = append(s.nodes, item)
 		s.unhealthy++
 	}
 }
 
+func (s *nodesStatus) issues(node v1.Node) ([]string, []string) {
+	return nodeIssues(node, s.now, s.serverVersion, s.config.Nodes)
+}
+
+// impact classifies an unhealthy node, a node in maintenance or a ready and schedulable node with
+// warnings only is a warning at most.
+func (s *nodesStatus) impact(node v1.Node) impact {
+	impact := s.policy.impact(&node, "")
+	if impact != impactFailure {
+		return impact
+	}
+
+	if inMaintenance, _ := nodeMaintenance(node, s.now); inMaintenance {
+		return impactWarning
+	}
+
+	isReady, cordoned, _ := getNodeConditions(node)
+	failures, _ := s.issues(node)
+	if nodeIsHealthy(isReady, cordoned) && len(failures) == 0 {
+		return impactWarning
+	}
+
+	return impact
+}
+
+// nodeIssues explains the problems of a node beyond readiness and cordoning. Resource pressure, stale
+// heartbeats and expired maintenance are failures, maintenance, NoExecute taints and kubelet versions skewed
+// from the API server are warnings.
+func nodeIssues(node v1.Node, now time.Time, serverVersion *version.Version, config NodesConfig) ([]string, []string) {
+	failures := []string{}
+	warnings := []string{}
+
+	inMaintenance, maintenance := nodeMaintenance(node, now)
+	if inMaintenance {
+		warnings = append(warnings, maintenance)
+	} else if maintenance != "" {
+		failures = append(failures, maintenance)
+	}
+
+	for _, condition := range nodePressureConditions {
+		if conditionStatus(node, condition) == string(v1.ConditionTrue) {
+			failures = append(failures, string(condition))
+		}
+	}
+
+	heartbeat, ok := lastHeartbeat(node)
+	if ok && now.Sub(heartbeat) > config.HeartbeatTimeout.Duration {
+		failures = append(failures, fmt.Sprintf("heartbeat is stale since %s", duration.HumanDuration(now.Sub(heartbeat))))
+	}
+
+	for _, taint := range noExecuteTaints(node) {
+		warnings = append(warnings, "tainted "+taint)
+	}
+
+	skew := kubeletSkew(node.Status.NodeInfo.KubeletVersion, serverVersion, config.MaxKubeletSkew)
+	if skew != "" {
+		warnings = append(warnings, skew)
+	}
+
+	return failures, warnings
+}
+
+// nodeMaintenance reports whether a node is in planned maintenance and describes the maintenance, the
+// description of expired or malformed maintenance annotations explains the problem.
+func nodeMaintenance(node v1.Node, now time.Time) (bool, string) {
+	value, ok := node.Annotations[maintenanceAnnotation]
+	if !ok {
+		return false, ""
+	}
+
+	if value == "true" {
+		return true, "in maintenance"
+	}
+
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false, fmt.Sprintf("invalid %s annotation %q, expected \"true\" or an RFC 3339 time", maintenanceAnnotation, value)
+	}
+
+	if now.After(until) {
+		return false, fmt.Sprintf("maintenance expired %s ago", duration.HumanDuration(now.Sub(until)))
+	}
+
+	return true, "in maintenance until " + until.Format(time.RFC3339)
+}
+
+// kubeletSkew describes an unsupported skew between the kubelet and the API server, it is empty if the
+// skew is supported or a version is unknown.
+func kubeletSkew(kubeletVersion string, serverVersion *version.Version, maxSkew int) string {
+	if serverVersion == nil {
+		return ""
+	}
+
+	kubelet, err := version.ParseGeneric(kubeletVersion)
+	if err != nil {
+		return ""
+	}
+
+	if kubelet.Major() != serverVersion.Major() || kubelet.Minor() > serverVersion.Minor() {
+		return fmt.Sprintf("kubelet %s is newer than the API server %s", kubeletVersion, serverVersion)
+	}
+
+	if int(serverVersion.Minor()-kubelet.Minor()) > maxSkew {
+		return fmt.Sprintf("kubelet %s is more than %d minor versions older than the API server %s", kubeletVersion, maxSkew, serverVersion)
+	}
+
+	return ""
+}
+
+// conditionStatus is the status of a node condition, it is empty if the node does not report the condition.
+func conditionStatus(node v1.Node, conditionType v1.NodeConditionType) string {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == conditionType {
+			return string(condition.Status)
+		}
+	}
+
+	return ""
+}
+
+// noExecuteTaints lists the taints evicting pods from a node, except the taints of nodes which are not ready.
+func noExecuteTaints(node v1.Node) []string {
+	taints := []string{}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != v1.TaintEffectNoExecute || slices.Contains(nodeLifecycleTaints, taint.Key) {
+			continue
+		}
+
+		taints = append(taints, taint.ToString())
+	}
+
+	return taints
+}
+
+// lastHeartbeat is the time the kubelet last reported the Ready condition.
+func lastHeartbeat(node v1.Node) (time.Time, bool) {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady && !condition.LastHeartbeatTime.IsZero() {
+			return condition.LastHeartbeatTime.Time, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func formatHeartbeat(node v1.Node, now time.Time) string {
+	heartbeat, ok := lastHeartbeat(node)
+	if !ok {
+		return ""
+	}
+
+	return duration.HumanDuration(now.Sub(heartbeat)) + " ago"
+}
+
 func getNodeConditions(node v1.Node) (bool, bool, []string) {
 	messages := make([]string, 0)
 	ready := false
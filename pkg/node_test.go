@@ -3,8 +3,11 @@ package k8status
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
 )
 
 func Test_getNodeConditions(t *testing.T) {
@@ -88,3 +91,135 @@ func Test_getNodeConditions(t *testing.T) {
 		})
 	}
 }
+
+func Test_nodeIssues(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultConfig().Nodes
+	server := version.MustParseGeneric("v1.30.2")
+
+	node := func(kubelet string, heartbeat time.Duration, conditions []v1.NodeCondition, taints []v1.Taint) v1.Node {
+		conditions = append(conditions, v1.NodeCondition{
+			Type:              v1.NodeReady,
+			Status:            v1.ConditionTrue,
+			LastHeartbeatTime: metav1.NewTime(now.Add(-heartbeat)),
+		})
+
+		return v1.Node{
+			Spec: v1.NodeSpec{Taints: taints},
+			Status: v1.NodeStatus{
+				Conditions: conditions,
+				NodeInfo:   v1.NodeSystemInfo{KubeletVersion: kubelet},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		node     v1.Node
+		server   *version.Version
+		failures []string
+		warnings []string
+	}{
+		{
+			name:     "healthy",
+			node:     node("v1.29.4", time.Minute, nil, nil),
+			server:   server,
+			failures: []string{},
+			warnings: []string{},
+		},
+		{
+			name: "memory pressure",
+			node: node("v1.30.2", time.Minute, []v1.NodeCondition{
+				{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue},
+				{Type: v1.NodeDiskPressure, Status: v1.ConditionFalse},
+			}, nil),
+			server:   server,
+			failures: []string{"MemoryPressure"},
+			warnings: []string{},
+		},
+		{
+			name:     "stale heartbeat",
+			node:     node("v1.30.2", 20*time.Minute, nil, nil),
+			server:   server,
+			failures: []string{"heartbeat is stale since 20m"},
+			warnings: []string{},
+		},
+		{
+			name: "NoExecute taints",
+			node: node("v1.30.2", time.Minute, nil, []v1.Taint{
+				{Key: "maintenance", Value: "kernel", Effect: v1.TaintEffectNoExecute},
+				{Key: "dedicated", Value: "db", Effect: v1.TaintEffectNoSchedule},
+				{Key: v1.TaintNodeUnreachable, Effect: v1.TaintEffectNoExecute},
+			}),
+			server:   server,
+			failures: []string{},
+			warnings: []string{"tainted maintenance=kernel:NoExecute"},
+		},
+		{
+			name:     "kubelet too old",
+			node:     node("v1.26.9", time.Minute, nil, nil),
+			server:   server,
+			failures: []string{},
+			warnings: []string{"kubelet v1.26.9 is more than 3 minor versions older than the API server 1.30.2"},
+		},
+		{
+			name:     "kubelet newer than server",
+			node:     node("v1.31.0", time.Minute, nil, nil),
+			server:   server,
+			failures: []string{},
+			warnings: []string{"kubelet v1.31.0 is newer than the API server 1.30.2"},
+		},
+		{
+			name:     "unknown server version",
+			node:     node("v1.20.0", time.Minute, nil, nil),
+			failures: []string{},
+			warnings: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures, warnings := nodeIssues(tt.node, now, tt.server, config)
+			if !reflect.DeepEqual(failures, tt.failures) {
+				t.Errorf("nodeIssues() failures = %v, want %v", failures, tt.failures)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("nodeIssues() warnings = %v, want %v", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func Test_nodeMaintenance(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		inMaintenance bool
+		description   string
+	}{
+		{name: "no annotation", inMaintenance: false, description: ""},
+		{name: "open-ended", annotations: map[string]string{maintenanceAnnotation: "true"}, inMaintenance: true, description: "in maintenance"},
+		{name: "until", annotations: map[string]string{maintenanceAnnotation: "2024-05-01T14:00:00Z"}, inMaintenance: true, description: "in maintenance until 2024-05-01T14:00:00Z"},
+		{name: "expired", annotations: map[string]string{maintenanceAnnotation: "2024-05-01T11:30:00Z"}, inMaintenance: false, description: "maintenance expired 30m ago"},
+		{
+			name:          "malformed",
+			annotations:   map[string]string{maintenanceAnnotation: "tomorrow"},
+			inMaintenance: false,
+			description:   `invalid k8status.io/maintenance annotation "tomorrow", expected "true" or an RFC 3339 time`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+
+			inMaintenance, description := nodeMaintenance(node, now)
+			if inMaintenance != tt.inMaintenance {
+				t.Errorf("nodeMaintenance() inMaintenance = %v, want %v", inMaintenance, tt.inMaintenance)
+			}
+			if description != tt.description {
+				t.Errorf("nodeMaintenance() description = %q, want %q", description, tt.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package k8status
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ownerKey identifies the controller of an object.
+type ownerKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (key ownerKey) String() string {
+	if key.kind == "" {
+		return ""
+	}
+
+	return key.kind + "/" + key.name
+}
+
+// ownerIndex resolves the controllers of pods, e.g. Pod → ReplicaSet → Deployment and Pod → Job → CronJob.
+type ownerIndex struct {
+	// parents maps ReplicaSets and Jobs to their controllers.
+	parents map[ownerKey]ownerKey
+	// severities maps the owners to their severity annotation.
+	severities map[ownerKey]string
+	// unhealthy lists the owners reported unhealthy by the selected checks.
+	unhealthy map[ownerKey]bool
+}
+
+// podOwners loads the owners of pods once per evaluation. Owners group the details and pass their severity
+// annotation on to their pods. If they can not be loaded pods are grouped by their direct controller.
+func (env *environment) podOwners(ctx context.Context) ownerIndex {
+	env.ownersOnce.Do(func() {
+		env.owners = ownerIndex{
+			parents:    map[ownerKey]ownerKey{},
+			severities: map[ownerKey]string{},
+			unhealthy:  map[ownerKey]bool{},
+		}
+
+		// owners which can not be listed are not marked
+		hide := env.config.Pods.HideOwnedByUnhealthy
+		mark := func(check string, healthy bool, kind string, meta metav1.ObjectMeta) {
+			env.owners.addSeverity(kind, meta)
+
+			if hide && env.checks[check] && !healthy {
+				env.owners.markUnhealthy(env.policy, kind, meta)
+			}
+		}
+
+		replicasets, err := listInScope(env, func(namespace string) ([]appsv1.ReplicaSet, error) {
+			return listReplicaSets(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range replicasets {
+				env.owners.addParent("ReplicaSet", item.ObjectMeta)
+			}
+		}
+
+		jobs, err := listInScope(env, func(namespace string) ([]batchv1.Job, error) {
+			return listJobs(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range jobs {
+				env.owners.addParent("Job", item.ObjectMeta)
+				mark("jobs", jobIsHealthy(item), "Job", item.ObjectMeta)
+			}
+		}
+
+		deployments, err := listInScope(env, func(namespace string) ([]appsv1.Deployment, error) {
+			return listDeployments(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range deployments {
+				mark("deployments", deploymentIsHealthy(item), "Deployment", item.ObjectMeta)
+			}
+		}
+
+		statefulsets, err := listInScope(env, func(namespace string) ([]appsv1.StatefulSet, error) {
+			return listStatefulSets(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range statefulsets {
+				mark("statefulsets", statefulsetIsHealthy(item), "StatefulSet", item.ObjectMeta)
+			}
+		}
+
+		daemonsets, err := listInScope(env, func(namespace string) ([]appsv1.DaemonSet, error) {
+			return listDaemonSets(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range daemonsets {
+				mark("daemonsets", daemonsetIsHealthy(item), "DaemonSet", item.ObjectMeta)
+			}
+		}
+
+		cronjobs, err := listInScope(env, func(namespace string) ([]batchv1.CronJob, error) {
+			return listCronJobs(ctx, env.client, namespace)
+		})
+		if err == nil {
+			for _, item := range cronjobs {
+				healthy := *item.Spec.Suspend || !missedTooManyRuns(item, env.config.Cronjobs.MaxMissedRuns)
+				mark("cronjobs", healthy, "CronJob", item.ObjectMeta)
+			}
+		}
+	})
+
+	return env.owners
+}
+
+// addSeverity remembers the severity annotation of an owner.
+func (index ownerIndex) addSeverity(kind string, meta metav1.ObjectMeta) {
+	severity, ok := meta.Annotations[severityAnnotation]
+	if !ok {
+		return
+	}
+
+	index.severities[ownerKey{kind: kind, namespace: meta.Namespace, name: meta.Name}] = severity
+}
+
+// markUnhealthy marks an unhealthy owner which is reported by its check, ignored owners are not reported.
+func (index ownerIndex) markUnhealthy(policy *policy, kind string, meta metav1.ObjectMeta) {
+	if policy.impact(&meta, meta.Namespace) == impactIgnored {
+		return
+	}
+
+	index.unhealthy[ownerKey{kind: kind, namespace: meta.Namespace, name: meta.Name}] = true
+}
+
+func (index ownerIndex) addParent(kind string, meta metav1.ObjectMeta) {
+	controller := metav1.GetControllerOfNoCopy(&meta)
+	if controller == nil {
+		return
+	}
+
+	key := ownerKey{kind: kind, namespace: meta.Namespace, name: meta.Name}
+	index.parents[key] = ownerKey{kind: controller.Kind, namespace: meta.Namespace, name: controller.Name}
+}
+
+// chain lists the controllers of a pod from its direct controller up to its top-level owner.
+func (index ownerIndex) chain(pod v1.Pod) []ownerKey {
+	controller := metav1.GetControllerOfNoCopy(&pod)
+	if controller == nil {
+		return nil
+	}
+
+	owner := ownerKey{kind: controller.Kind, namespace: pod.Namespace, name: controller.Name}
+	chain := []ownerKey{owner}
+
+	// ownership is not cyclic, the limit guards against broken references
+	for range 8 {
+		parent, ok := index.parents[owner]
+		if !ok {
+			break
+		}
+
+		chain = append(chain, parent)
+		owner = parent
+	}
+
+	return chain
+}
+
+// topLevel is the outermost known controller of a pod, it is empty for pods without a controller.
+func (index ownerIndex) topLevel(pod v1.Pod) ownerKey {
+	chain := index.chain(pod)
+	if len(chain) == 0 {
+		return ownerKey{}
+	}
+
+	return chain[len(chain)-1]
+}
+
+// severity is the severity annotation of the top-level owner of a pod, it is empty if the owner is not annotated.
+func (index ownerIndex) severity(pod v1.Pod) string {
+	return index.severities[index.topLevel(pod)]
+}
+
+// ownerUnhealthy reports whether any controller of a pod is reported unhealthy.
+func (index ownerIndex) ownerUnhealthy(pod v1.Pod) bool {
+	for _, owner := range index.chain(pod) {
+		if index.unhealthy[owner] {
+			return true
+		}
+	}
+
+	return false
+}
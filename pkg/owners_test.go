@@ -0,0 +1,77 @@
+package k8status
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ownerIndex(t *testing.T) {
+	controlled := func(kind, name string) metav1.ObjectMeta {
+		controller := true
+		return metav1.ObjectMeta{
+			Namespace:       "shop",
+			Name:            name + "-x",
+			OwnerReferences: []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}},
+		}
+	}
+	pod := func(kind, name string) v1.Pod {
+		return v1.Pod{ObjectMeta: controlled(kind, name)}
+	}
+
+	index := ownerIndex{parents: map[ownerKey]ownerKey{}, unhealthy: map[ownerKey]bool{}}
+	index.addParent("ReplicaSet", metav1.ObjectMeta{
+		Namespace:       "shop",
+		Name:            "web-5d8f",
+		OwnerReferences: controlled("Deployment", "web").OwnerReferences,
+	})
+	index.addParent("Job", metav1.ObjectMeta{
+		Namespace:       "shop",
+		Name:            "backup-2840",
+		OwnerReferences: controlled("CronJob", "backup").OwnerReferences,
+	})
+	index.addParent("ReplicaSet", metav1.ObjectMeta{
+		Namespace:       "shop",
+		Name:            "flaky-9a1c",
+		OwnerReferences: controlled("Deployment", "flaky").OwnerReferences,
+	})
+
+	policy := &policy{}
+	index.markUnhealthy(policy, "Deployment", metav1.ObjectMeta{Namespace: "shop", Name: "web"})
+	index.markUnhealthy(policy, "Deployment", metav1.ObjectMeta{
+		Namespace:   "shop",
+		Name:        "flaky",
+		Annotations: map[string]string{ignoreAnnotation: "true"},
+	})
+	index.markUnhealthy(policy, "StatefulSet", metav1.ObjectMeta{
+		Namespace:   "shop",
+		Name:        "cache",
+		Annotations: map[string]string{severityAnnotation: "warning"},
+	})
+
+	tests := []struct {
+		name          string
+		pod           v1.Pod
+		wantOwner     string
+		wantUnhealthy bool
+	}{
+		{name: "deployment", pod: pod("ReplicaSet", "web-5d8f"), wantOwner: "Deployment/web", wantUnhealthy: true},
+		{name: "cronjob", pod: pod("Job", "backup-2840"), wantOwner: "CronJob/backup"},
+		{name: "ignored owner", pod: pod("ReplicaSet", "flaky-9a1c"), wantOwner: "Deployment/flaky"},
+		{name: "warning owner", pod: pod("StatefulSet", "cache"), wantOwner: "StatefulSet/cache", wantUnhealthy: true},
+		{name: "statefulset", pod: pod("StatefulSet", "db"), wantOwner: "StatefulSet/db"},
+		{name: "orphaned replicaset", pod: pod("ReplicaSet", "api-7c9b"), wantOwner: "ReplicaSet/api-7c9b"},
+		{name: "no controller", pod: v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "debug"}}, wantOwner: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := index.topLevel(tt.pod).String(); got != tt.wantOwner {
+				t.Errorf("topLevel() = %q, want %q", got, tt.wantOwner)
+			}
+			if got := index.ownerUnhealthy(tt.pod); got != tt.wantUnhealthy {
+				t.Errorf("ownerUnhealthy() = %v, want %v", got, tt.wantUnhealthy)
+			}
+		})
+	}
+}
@@ -1,40 +1,120 @@
 package k8status
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"slices"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+// Categories of unhealthy pods, ordered by how specific they explain the failure.
+const (
+	podTerminating      = "Terminating"
+	podEvicted          = "Evicted"
+	podUnschedulable    = "Unschedulable"
+	podImagePullBackOff = "ImagePullBackOff"
+	podInitFailed       = "InitContainerFailed"
+	podOOMKilled        = "OOMKilled"
+	podCrashLoopBackOff = "CrashLoopBackOff"
+	podPending          = "Pending"
+	podRestarting       = "Restarting"
+	podNotReady         = "NotReady"
+)
+
+var podCategories = []string{
+	podTerminating,
+	podEvicted,
+	podUnschedulable,
+	podImagePullBackOff,
+	podInitFailed,
+	podOOMKilled,
+	podCrashLoopBackOff,
+	podPending,
+	podRestarting,
+	podNotReady,
+}
+
+var imagePullReasons = []string{"ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull"}
+
 type podsStatus struct {
-	total     int
-	ignored   int
-	healthy   int
-	pods      []v1.Pod
-	unhealthy int
+	config     *Config
+	policy     *policy
+	events     eventIndex
+	owners     ownerIndex
+	now        time.Time
+	restarts   map[types.UID]int32
+	total      int
+	ignored    int
+	warnings   int
+	healthy    int
+	pods       []v1.Pod
+	categories map[string]int
+	unhealthy  int
+	// hidden counts the unhealthy pods omitted from the details since their owner is reported unhealthy.
+	hidden int
 }
 
-func NewPodsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	podsList, err := client.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+func NewPodsStatus(ctx context.Context, env *environment) (status, error) {
+	pods, err := listInScope(env, func(namespace string) ([]v1.Pod, error) {
+		return listPods(ctx, env.client, namespace, labels.Everything())
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	pods := podsList.Items
-
 	status := &podsStatus{
-		pods: []v1.Pod{},
+		config:     env.config,
+		policy:     env.policy,
+		now:        time.Now(),
+		pods:       []v1.Pod{},
+		categories: map[string]int{},
 	}
+	status.restarts = env.client.restarts.observe(pods, status.now, env.config.Pods.RestartWindow.Duration)
 	status.add(pods)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+		// pods inherit the severity annotation of their owner
+		status.owners = env.podOwners(ctx)
+		status.classify()
+		status.groupByOwner()
+	}
+
 	return status, nil
 }
 
 func (s *podsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d pods are healthy.\n", s.ignored, s.healthy, s.total)
+	err := printSummary(w, "%d of %d pods are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range podCategories {
+		count := s.categories[category]
+		if count == 0 {
+			continue
+		}
+
+		_, err := fmt.Fprintf(w, "- %d %s\n", count, category)
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.hidden > 0 {
+		_, err := fmt.Fprintf(w, "- %d hidden, their owner is reported unhealthy\n", s.hidden)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (s *podsStatus) Details(w io.Writer, colored bool) error {
@@ -42,72 +122,211 @@ func (s *podsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *podsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 51
+	if s.Severity() == SeverityCritical {
+		return exitCodePods
 	}
 
 	return 0
 }
 
+func (s *podsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *podsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *podsStatus) toTable() Table {
-	header := []string{"Namespace", "Pod", "Phase", "Status", "Containers Ready", "Containers Expected", "Node"}
+	header := []string{"Namespace", "Pod", "Owner", "Phase", "Status", "Restarts", "Containers Ready", "Containers Expected", "Node", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.pods {
-		status := ""
-		containerStatus := []v1.ContainerStatus{}
-		containerStatus = append(containerStatus, item.Status.InitContainerStatuses...)
-		containerStatus = append(containerStatus, item.Status.ContainerStatuses...)
-		containerStatus = append(containerStatus, item.Status.EphemeralContainerStatuses...)
-		for _, s := range containerStatus {
-			if s.State.Waiting != nil {
-				status = s.State.Waiting.Reason
-			}
-			if s.State.Terminated != nil {
-				status = s.State.Terminated.Reason
-			}
-		}
 		row := []string{
 			item.Namespace,
 			item.Name,
+			s.owners.topLevel(item).String(),
 			string(item.Status.Phase),
-			status,
+			s.category(item),
+			fmt.Sprintf("%d", getRestarts(item)),
 			fmt.Sprintf("%d", getReadyContainers(item)),
 			fmt.Sprintf("%d", len(item.Spec.Containers)),
 			item.Spec.NodeName,
+			s.events.last(item.UID),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.impact(item).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
-func (s *podsStatus) add(pvcs []v1.Pod) {
-	s.total += len(pvcs)
+func (s *podsStatus) add(pods []v1.Pod) {
+	s.total += len(pods)
 
-	for _, item := range pvcs {
-		if podIsHealthy(item) {
+	for _, item := range pods {
+		category := s.category(item)
+		if category == "" {
 			s.healthy++
 			continue
 		}
 
-		ignored := isCiOrLabNamespace(item.Namespace)
-		if ignored {
+		s.categories[category]++
+		s.pods = append(s.pods, item)
+		s.unhealthy++
+	}
+}
+
+// classify counts the ignored unhealthy pods and the warnings, once the owners of the pods are known.
+func (s *podsStatus) classify() {
+	for _, item := range s.pods {
+		switch s.impact(item) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
+	}
+}
 
-		s.pods = append(s.pods, item)
-		s.unhealthy++
+// groupByOwner sorts the unhealthy pods by their top-level owner and hides the pods
+// whose owner is reported unhealthy, if configured.
+func (s *podsStatus) groupByOwner() {
+	owners := s.owners
+
+	if s.config.Pods.HideOwnedByUnhealthy {
+		s.pods = slices.DeleteFunc(s.pods, func(item v1.Pod) bool {
+			if owners.ownerUnhealthy(item) {
+				s.hidden++
+				return true
+			}
+
+			return false
+		})
+	}
+
+	slices.SortStableFunc(s.pods, func(a, b v1.Pod) int {
+		return cmp.Or(
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(owners.topLevel(a).String(), owners.topLevel(b).String()),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+func (s *podsStatus) category(item v1.Pod) string {
+	return podCategory(item, s.now, s.config.Pods, s.restarts[item.UID])
+}
+
+// impact classifies an unhealthy pod, a ready pod which restarts is a warning unless it is annotated critical.
+// Pods without a severity annotation of their own inherit the one of their top-level owner.
+func (s *podsStatus) impact(item v1.Pod) impact {
+	severity, ok := item.Annotations[severityAnnotation]
+	if !ok {
+		severity = s.owners.severity(item)
+	}
+
+	impact := s.policy.annotatedImpact(&item, item.Namespace, severity)
+	if impact == impactFailure && severity != "critical" && s.category(item) == podRestarting {
+		return impactWarning
 	}
+
+	return impact
 }
 
-func podIsHealthy(item v1.Pod) bool {
+// podCategory explains why a pod is unhealthy, it is empty for healthy pods. restarts counts the restarts
+// within the restart window. Pods shutting down or starting up within their grace periods are healthy.
+func podCategory(item v1.Pod, now time.Time, config PodsConfig, restarts int32) string {
+	// the deletion timestamp is set to the end of the termination grace period of the pod
+	if item.DeletionTimestamp != nil {
+		if now.Sub(item.DeletionTimestamp.Time) > config.TerminatingGracePeriod.Duration {
+			return podTerminating
+		}
+
+		return ""
+	}
+
+	if item.Status.Phase == v1.PodSucceeded {
+		return ""
+	}
+
+	pending := item.Status.Phase == v1.PodPending
+	if pending && now.Sub(item.CreationTimestamp.Time) <= config.PendingGracePeriod.Duration {
+		return ""
+	}
+
+	if item.Status.Phase == v1.PodFailed && item.Status.Reason == "Evicted" {
+		return podEvicted
+	}
+
+	for _, condition := range item.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable {
+			return podUnschedulable
+		}
+	}
+
+	containers := append(append([]v1.ContainerStatus{}, item.Status.InitContainerStatuses...), item.Status.ContainerStatuses...)
+	for _, container := range containers {
+		if container.State.Waiting != nil && slices.Contains(imagePullReasons, container.State.Waiting.Reason) {
+			return podImagePullBackOff
+		}
+	}
+
+	for _, container := range item.Status.InitContainerStatuses {
+		failed := container.State.Terminated != nil && container.State.Terminated.ExitCode != 0
+		crashing := container.State.Waiting != nil && container.State.Waiting.Reason == "CrashLoopBackOff"
+		if failed || crashing {
+			return podInitFailed
+		}
+	}
+
 	allReady := len(item.Spec.Containers) == getReadyContainers(item)
-	succeeded := item.Status.Phase == v1.PodSucceeded
-	return allReady || succeeded
+
+	if !allReady {
+		for _, container := range item.Status.ContainerStatuses {
+			if terminatedReason(container.State) == "OOMKilled" || terminatedReason(container.LastTerminationState) == "OOMKilled" {
+				return podOOMKilled
+			}
+		}
+
+		for _, container := range item.Status.ContainerStatuses {
+			if container.State.Waiting != nil && container.State.Waiting.Reason == "CrashLoopBackOff" {
+				return podCrashLoopBackOff
+			}
+		}
+
+		if pending {
+			return podPending
+		}
+
+		return podNotReady
+	}
+
+	if restarts >= config.MinRestarts {
+		return podRestarting
+	}
+
+	return ""
+}
+
+func terminatedReason(state v1.ContainerState) string {
+	if state.Terminated == nil {
+		return ""
+	}
+
+	return state.Terminated.Reason
 }
 
 func getReadyContainers(item v1.Pod) int {
@@ -119,3 +338,11 @@ func getReadyContainers(item v1.Pod) int {
 	}
 	return containerReady
 }
+
+func getRestarts(item v1.Pod) int32 {
+	restarts := int32(0)
+	for _, containerStatus := range item.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+	return restarts
+}
@@ -0,0 +1,223 @@
+package k8status
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_podCategory(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultConfig().Pods
+
+	running := func(statuses ...v1.ContainerStatus) v1.Pod {
+		containers := []v1.Container{}
+		for range statuses {
+			containers = append(containers, v1.Container{})
+		}
+
+		return v1.Pod{
+			Spec:   v1.PodSpec{Containers: containers},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: statuses},
+		}
+	}
+	waiting := func(reason string) v1.ContainerState {
+		return v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}
+	}
+	terminated := func(reason string, exitCode int32, age time.Duration) v1.ContainerState {
+		return v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   exitCode,
+			FinishedAt: metav1.NewTime(now.Add(-age)),
+		}}
+	}
+
+	deleted := running(v1.ContainerStatus{Ready: true})
+	deleted.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Hour)}
+
+	shuttingDown := running(v1.ContainerStatus{})
+	shuttingDown.DeletionTimestamp = &metav1.Time{Time: now.Add(10 * time.Second)}
+
+	starting := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Second))},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{}}},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+
+	stuckPending := starting
+	stuckPending.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+
+	evicted := v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"}}
+
+	unschedulable := v1.Pod{
+		Spec: v1.PodSpec{Containers: []v1.Container{{}}},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable},
+			},
+		},
+	}
+
+	initFailed := running(v1.ContainerStatus{State: waiting("PodInitializing")})
+	initFailed.Status.InitContainerStatuses = []v1.ContainerStatus{{State: terminated("Error", 1, time.Minute)}}
+
+	tests := []struct {
+		name     string
+		pod      v1.Pod
+		restarts int32
+		want     string
+	}{
+		{name: "ready", pod: running(v1.ContainerStatus{Ready: true}), want: ""},
+		{name: "succeeded", pod: v1.Pod{Status: v1.PodStatus{Phase: v1.PodSucceeded}}, want: ""},
+		{name: "stuck terminating", pod: deleted, want: podTerminating},
+		{name: "terminating within grace period", pod: shuttingDown, want: ""},
+		{name: "pending within grace period", pod: starting, want: ""},
+		{name: "stuck pending", pod: stuckPending, want: podPending},
+		{name: "evicted", pod: evicted, want: podEvicted},
+		{name: "unschedulable", pod: unschedulable, want: podUnschedulable},
+		{name: "image pull", pod: running(v1.ContainerStatus{State: waiting("ErrImagePull")}), want: podImagePullBackOff},
+		{name: "init container failed", pod: initFailed, want: podInitFailed},
+		{
+			name: "out of memory",
+			pod: running(v1.ContainerStatus{
+				State:                waiting("CrashLoopBackOff"),
+				LastTerminationState: terminated("OOMKilled", 137, time.Minute),
+			}),
+			want: podOOMKilled,
+		},
+		{name: "crash loop", pod: running(v1.ContainerStatus{State: waiting("CrashLoopBackOff")}), want: podCrashLoopBackOff},
+		{name: "not ready", pod: running(v1.ContainerStatus{Ready: true}, v1.ContainerStatus{}), want: podNotReady},
+		{
+			name: "ready but restarting",
+			pod: running(v1.ContainerStatus{
+				Ready:                true,
+				RestartCount:         5,
+				LastTerminationState: terminated("Error", 1, 5*time.Minute),
+			}),
+			restarts: 3,
+			want:     podRestarting,
+		},
+		{
+			name: "restarted long ago",
+			pod: running(v1.ContainerStatus{
+				Ready:                true,
+				RestartCount:         5,
+				LastTerminationState: terminated("Error", 1, time.Hour),
+			}),
+			want: "",
+		},
+		{
+			name: "restarted rarely",
+			pod: running(v1.ContainerStatus{
+				Ready:                true,
+				RestartCount:         5,
+				LastTerminationState: terminated("Error", 1, 5*time.Minute),
+			}),
+			restarts: 1,
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := podCategory(tt.pod, now, config, tt.restarts)
+			if got != tt.want {
+				t.Errorf("podCategory() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_podsStatus_toTable(t *testing.T) {
+	controller := true
+	pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "shop",
+		Name:            "web-1",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "web", Controller: &controller}},
+	}}
+	config := DefaultConfig()
+	status := &podsStatus{config: &config, policy: &policy{}, pods: []v1.Pod{pod}}
+
+	table := status.toTable()
+
+	// junit names the testcases of the pods by the first two columns
+	if got := junitTestCaseName(table.Header, table.Rows[0]); got != "shop/web-1" {
+		t.Errorf("junitTestCaseName() = %q, want %q", got, "shop/web-1")
+	}
+	if got := table.Rows[0][2]; table.Header[2] != "Owner" || got != "StatefulSet/web" {
+		t.Errorf("%s column = %q, want %q", table.Header[2], got, "StatefulSet/web")
+	}
+}
+
+func Test_podsStatus_impact(t *testing.T) {
+	controller := true
+	pod := func(owner string, annotations map[string]string) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "shop",
+				Name:            owner + "-5d8f-x",
+				Annotations:     annotations,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: owner + "-5d8f", Controller: &controller}},
+			},
+			Spec:   v1.PodSpec{Containers: []v1.Container{{}}},
+			Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{}}},
+		}
+	}
+
+	owners := ownerIndex{parents: map[ownerKey]ownerKey{}, severities: map[ownerKey]string{}, unhealthy: map[ownerKey]bool{}}
+	for _, name := range []string{"web", "batch", "api"} {
+		owners.addParent("ReplicaSet", metav1.ObjectMeta{
+			Namespace:       "shop",
+			Name:            name + "-5d8f",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: name, Controller: &controller}},
+		})
+	}
+	owners.addSeverity("Deployment", metav1.ObjectMeta{
+		Namespace:   "shop",
+		Name:        "batch",
+		Annotations: map[string]string{severityAnnotation: "ignore"},
+	})
+	owners.addSeverity("Deployment", metav1.ObjectMeta{
+		Namespace:   "shop",
+		Name:        "api",
+		Annotations: map[string]string{severityAnnotation: "warning"},
+	})
+
+	restarting := func(annotations map[string]string) v1.Pod {
+		restarting := pod("web", annotations)
+		restarting.UID = "web-restarting"
+		restarting.Status.ContainerStatuses[0].Ready = true
+		return restarting
+	}
+
+	config := DefaultConfig()
+	status := &podsStatus{
+		config:   &config,
+		policy:   &policy{},
+		owners:   owners,
+		restarts: map[types.UID]int32{"web-restarting": config.Pods.MinRestarts},
+	}
+
+	tests := []struct {
+		name string
+		pod  v1.Pod
+		want impact
+	}{
+		{name: "not annotated", pod: pod("web", nil), want: impactFailure},
+		{name: "owner ignored", pod: pod("batch", nil), want: impactIgnored},
+		{name: "owner warning", pod: pod("api", nil), want: impactWarning},
+		{name: "pod annotation wins", pod: pod("batch", map[string]string{severityAnnotation: "critical"}), want: impactFailure},
+		{name: "restarting", pod: restarting(nil), want: impactWarning},
+		{name: "restarting annotated critical", pod: restarting(map[string]string{severityAnnotation: "critical"}), want: impactFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.impact(tt.pod); got != tt.want {
+				t.Errorf("impact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package k8status
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ignoreAnnotation   = "k8status.io/ignore"
+	severityAnnotation = "k8status.io/severity"
+)
+
+// impact is the effect an unhealthy object has on the report.
+type impact int
+
+const (
+	// impactFailure fails the report.
+	impactFailure impact = iota
+	// impactWarning reports the object without failing the report.
+	impactWarning
+	// impactIgnored counts the object as ignored.
+	impactIgnored
+)
+
+// IgnoreRule matches objects whose unhealthy state does not fail the report.
+// All conditions set in a rule have to match.
+type IgnoreRule struct {
+	// Namespaces are glob patterns matching the namespace of the object.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceRegexp is a regular expression matching the namespace of the object.
+	NamespaceRegexp string `json:"namespaceRegexp,omitempty"`
+	// Selector is a label selector matching the labels of the object.
+	Selector string `json:"selector,omitempty"`
+	// NamespaceSelector is a label selector matching the labels of the namespace of the object.
+	NamespaceSelector string `json:"namespaceSelector,omitempty"`
+}
+
+type ignoreRule struct {
+	namespaces        []string
+	namespaceRegexp   *regexp.Regexp
+	selector          labels.Selector
+	namespaceSelector labels.Selector
+}
+
+// policy decides which unhealthy objects are ignored.
+type policy struct {
+	rules      []ignoreRule
+	namespaces map[string]labels.Set
+	// namespacesErr explains why the namespace selectors of the ignore rules are not applied.
+	namespacesErr error
+}
+
+// newPolicy compiles the ignore rules and looks up the labels of the evaluated namespaces if a rule selects them.
+// If the labels can not be read, rules with a namespace selector do not match.
+func newPolicy(ctx context.Context, client *KubernetesClient, config *Config, scope []string) (*policy, error) {
+	rules, err := compileIgnoreRules(config.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &policy{
+		rules: rules,
+	}
+
+	if !p.needsNamespaceLabels() {
+		return p, nil
+	}
+
+	namespaces, err := namespaceLabels(ctx, client, scope)
+	if err != nil {
+		p.namespacesErr = fmt.Errorf("look up namespace labels for ignore rules: %v", err)
+		return p, nil
+	}
+
+	p.namespaces = namespaces
+
+	return p, nil
+}
+
+// namespaceLabels looks up the labels of the namespaces in scope, "" stands for all namespaces. Without permission
+// to list all namespaces, the namespaces in scope are read one by one.
+func namespaceLabels(ctx context.Context, client *KubernetesClient, scope []string) (map[string]labels.Set, error) {
+	allowed, err := canI(ctx, client, "list", resourceNamespaces, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := map[string]labels.Set{}
+
+	if allowed {
+		items, err := listNamespaces(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range items {
+			namespaces[namespace.Name] = namespace.Labels
+		}
+
+		return namespaces, nil
+	}
+
+	for _, name := range scope {
+		if name == "" {
+			return nil, fmt.Errorf("listing namespaces is not permitted")
+		}
+
+		namespace, err := getNamespace(ctx, client, name)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces[name] = namespace.Labels
+	}
+
+	return namespaces, nil
+}
+
+// unapplied notes in summaries that the namespace selectors of the ignore rules are not applied.
+func (p *policy) unapplied() string {
+	if p.namespacesErr == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("- ignore rules with a namespace selector are not applied: %v\n", p.namespacesErr)
+}
+
+func compileIgnoreRules(rules []IgnoreRule) ([]ignoreRule, error) {
+	compiled := []ignoreRule{}
+
+	for i, rule := range rules {
+		c, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("ignore rule %d: %v", i+1, err)
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
+
+func (r IgnoreRule) compile() (ignoreRule, error) {
+	compiled := ignoreRule{
+		namespaces: r.Namespaces,
+	}
+
+	if len(r.Namespaces) == 0 && r.NamespaceRegexp == "" && r.Selector == "" && r.NamespaceSelector == "" {
+		return ignoreRule{}, fmt.Errorf("rule has no conditions")
+	}
+
+	for _, pattern := range r.Namespaces {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return ignoreRule{}, fmt.Errorf("namespace pattern %q: %v", pattern, err)
+		}
+	}
+
+	if r.NamespaceRegexp != "" {
+		namespaceRegexp, err := regexp.Compile(r.NamespaceRegexp)
+		if err != nil {
+			return ignoreRule{}, fmt.Errorf("namespace regexp: %v", err)
+		}
+
+		compiled.namespaceRegexp = namespaceRegexp
+	}
+
+	if r.Selector != "" {
+		selector, err := labels.Parse(r.Selector)
+		if err != nil {
+			return ignoreRule{}, fmt.Errorf("selector: %v", err)
+		}
+
+		compiled.selector = selector
+	}
+
+	if r.NamespaceSelector != "" {
+		namespaceSelector, err := labels.Parse(r.NamespaceSelector)
+		if err != nil {
+			return ignoreRule{}, fmt.Errorf("namespace selector: %v", err)
+		}
+
+		compiled.namespaceSelector = namespaceSelector
+	}
+
+	return compiled, nil
+}
+
+func (p *policy) needsNamespaceLabels() bool {
+	for _, rule := range p.rules {
+		if rule.namespaceSelector != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// impact classifies an unhealthy object. The severity annotation set by the owner of the object
+// takes precedence over the ignore rules.
+func (p *policy) impact(obj metav1.Object, namespace string) impact {
+	return p.annotatedImpact(obj, namespace, obj.GetAnnotations()[severityAnnotation])
+}
+
+// annotatedImpact classifies an unhealthy object by the given value of the severity annotation,
+// e.g. the one inherited from the owner of a pod.
+func (p *policy) annotatedImpact(obj metav1.Object, namespace string, severity string) impact {
+	switch severity {
+	case "critical":
+		return impactFailure
+	case "warning":
+		return impactWarning
+	case "ignore":
+		return impactIgnored
+	}
+
+	if p.ignored(obj, namespace) {
+		return impactIgnored
+	}
+
+	return impactFailure
+}
+
+// ignored reports if an unhealthy object is ignored. namespace is the namespace the object belongs to,
+// which is the object itself for namespaces and the namespace of the bound claim for persistent volumes.
+func (p *policy) ignored(obj metav1.Object, namespace string) bool {
+	if obj.GetAnnotations()[ignoreAnnotation] == "true" {
+		return true
+	}
+
+	namespaceLabels, known := p.namespaces[namespace]
+
+	for _, rule := range p.rules {
+		if rule.namespaceSelector != nil && !known {
+			continue
+		}
+
+		if rule.matches(obj, namespace, namespaceLabels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r ignoreRule) matches(obj metav1.Object, namespace string, namespaceLabels labels.Set) bool {
+	if len(r.namespaces) != 0 && !matchesAnyPattern(r.namespaces, namespace) {
+		return false
+	}
+
+	if r.namespaceRegexp != nil && (namespace == "" || !r.namespaceRegexp.MatchString(namespace)) {
+		return false
+	}
+
+	if r.selector != nil && !r.selector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+
+	if r.namespaceSelector != nil && (namespace == "" || !r.namespaceSelector.Matches(namespaceLabels)) {
+		return false
+	}
+
+	return true
+}
+
+func matchesAnyPattern(patterns []string, namespace string) bool {
+	if namespace == "" {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		matched, _ := path.Match(pattern, namespace)
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
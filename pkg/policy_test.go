@@ -0,0 +1,213 @@
+package k8status
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func Test_policy_ignored(t *testing.T) {
+	namespaces := map[string]labels.Set{
+		"team-a": {"environment": "ci"},
+		"team-b": {"environment": "production"},
+	}
+
+	tests := []struct {
+		name      string
+		rules     []IgnoreRule
+		object    metav1.Object
+		namespace string
+		want      bool
+	}{
+		{
+			name:      "no rules",
+			object:    &v1.Pod{},
+			namespace: "ci-test",
+			want:      false,
+		},
+		{
+			name:      "ignore annotation",
+			object:    &v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ignoreAnnotation: "true"}}},
+			namespace: "default",
+			want:      true,
+		},
+		{
+			name:      "namespace glob",
+			rules:     []IgnoreRule{{Namespaces: []string{"*-ci-*"}}},
+			object:    &v1.Pod{},
+			namespace: "test-ci-test",
+			want:      true,
+		},
+		{
+			name:      "namespace glob without match",
+			rules:     []IgnoreRule{{Namespaces: []string{"ci-*"}}},
+			object:    &v1.Pod{},
+			namespace: "test-ci",
+			want:      false,
+		},
+		{
+			name:      "namespace regexp",
+			rules:     []IgnoreRule{{NamespaceRegexp: "^review-[0-9]+$"}},
+			object:    &v1.Pod{},
+			namespace: "review-42",
+			want:      true,
+		},
+		{
+			name:      "object selector",
+			rules:     []IgnoreRule{{Selector: "app=flaky"}},
+			object:    &v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "flaky"}}},
+			namespace: "default",
+			want:      true,
+		},
+		{
+			name:      "namespace selector",
+			rules:     []IgnoreRule{{NamespaceSelector: "environment=ci"}},
+			object:    &v1.Pod{},
+			namespace: "team-a",
+			want:      true,
+		},
+		{
+			name:      "namespace selector without match",
+			rules:     []IgnoreRule{{NamespaceSelector: "environment=ci"}},
+			object:    &v1.Pod{},
+			namespace: "team-b",
+			want:      false,
+		},
+		{
+			name:      "namespace selector of an unknown namespace",
+			rules:     []IgnoreRule{{NamespaceSelector: "environment!=production"}},
+			object:    &v1.Pod{},
+			namespace: "team-c",
+			want:      false,
+		},
+		{
+			name:      "all conditions of a rule have to match",
+			rules:     []IgnoreRule{{Namespaces: []string{"team-*"}, Selector: "app=flaky"}},
+			object:    &v1.Pod{},
+			namespace: "team-a",
+			want:      false,
+		},
+		{
+			name:      "namespace rules do not match cluster scoped objects",
+			rules:     []IgnoreRule{{Namespaces: []string{"*"}}},
+			object:    &v1.Node{},
+			namespace: "",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := compileIgnoreRules(tt.rules)
+			if err != nil {
+				t.Fatalf("compileIgnoreRules() error = %v", err)
+			}
+
+			p := &policy{
+				rules:      rules,
+				namespaces: namespaces,
+			}
+
+			got := p.ignored(tt.object, tt.namespace)
+			if got != tt.want {
+				t.Errorf("policy.ignored() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_policy_impact(t *testing.T) {
+	ciRule := []IgnoreRule{{Namespaces: []string{"ci-*"}}}
+
+	tests := []struct {
+		name      string
+		severity  string
+		namespace string
+		want      impact
+	}{
+		{
+			name:      "unhealthy objects fail the report",
+			namespace: "default",
+			want:      impactFailure,
+		},
+		{
+			name:      "ignore rules apply without annotation",
+			namespace: "ci-test",
+			want:      impactIgnored,
+		},
+		{
+			name:      "critical overrides ignore rules",
+			severity:  "critical",
+			namespace: "ci-test",
+			want:      impactFailure,
+		},
+		{
+			name:      "warning",
+			severity:  "warning",
+			namespace: "default",
+			want:      impactWarning,
+		},
+		{
+			name:      "ignore",
+			severity:  "ignore",
+			namespace: "default",
+			want:      impactIgnored,
+		},
+		{
+			name:      "unknown severity falls back to ignore rules",
+			severity:  "page-me",
+			namespace: "default",
+			want:      impactFailure,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := compileIgnoreRules(ciRule)
+			if err != nil {
+				t.Fatalf("compileIgnoreRules() error = %v", err)
+			}
+
+			p := &policy{
+				rules: rules,
+			}
+
+			deployment := &appsv1.Deployment{}
+			if tt.severity != "" {
+				deployment.Annotations = map[string]string{severityAnnotation: tt.severity}
+			}
+
+			got := p.impact(deployment, tt.namespace)
+			if got != tt.want {
+				t.Errorf("policy.impact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newPolicy_namespacesDenied(t *testing.T) {
+	config := DefaultConfig()
+	config.Ignore = []IgnoreRule{{NamespaceSelector: "environment=ci"}}
+
+	// listing namespaces is denied, the namespaces can not be read one by one for all namespaces
+	client := &KubernetesClient{
+		reviews: map[permission]bool{{verb: "list", resource: resourceNamespaces}: false},
+	}
+
+	p, err := newPolicy(context.Background(), client, &config, []string{""})
+	if err != nil {
+		t.Fatalf("newPolicy() error = %v", err)
+	}
+
+	if p.ignored(&v1.Pod{}, "team-a") {
+		t.Errorf("policy.ignored() = true, want false")
+	}
+
+	note := p.unapplied()
+	if !strings.Contains(note, "ignore rules with a namespace selector are not applied") {
+		t.Errorf("policy.unapplied() = %q", note)
+	}
+}
@@ -0,0 +1,284 @@
+package k8status
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// roleName names the ClusterRole and Roles printed by preflight.
+const roleName = "k8status"
+
+// requiredPermissions lists the permissions needed by the checks. Namespaced resources are listed
+// in the given namespaces, "" stands for all namespaces.
+func requiredPermissions(checks []check, config Config, namespaces []string) []permission {
+	permissions := []permission{}
+	add := func(permission permission) {
+		if !slices.Contains(permissions, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	for _, check := range checks {
+		for _, resource := range check.resources {
+			if !resource.namespaced {
+				add(permission{verb: "list", resource: resource})
+				continue
+			}
+
+			for _, namespace := range namespaces {
+				add(permission{verb: "list", resource: resource, namespace: namespace})
+			}
+		}
+
+		if check.access != nil {
+			for _, permission := range check.access(config) {
+				add(permission)
+			}
+		}
+	}
+
+	// the config is validated while loading it
+	rules, _ := compileIgnoreRules(config.Ignore)
+	if (&policy{rules: rules}).needsNamespaceLabels() {
+		add(permission{verb: "list", resource: resourceNamespaces})
+	}
+
+	return permissions
+}
+
+// Preflight verifies that the current identity has all permissions needed by the selected checks
+// and prints the missing ones.
+func Preflight(ctx context.Context, client *KubernetesClient, options Options) error {
+	checks, err := selectChecks(options.Only, options.Skip, options.Config)
+	if err != nil {
+		return err
+	}
+
+	namespaces := client.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	missing := [][]string{}
+	for _, permission := range requiredPermissions(checks, options.Config, namespaces) {
+		allowed, err := reviewAccess(ctx, client, permission)
+		if err != nil {
+			return err
+		}
+
+		if allowed {
+			continue
+		}
+
+		resource := permission.resource.String()
+		if permission.subresource != "" {
+			resource += "/" + permission.subresource
+		}
+
+		if permission.name != "" {
+			resource += " " + permission.name
+		}
+
+		namespace := permission.namespace
+		if namespace == "" && permission.resource.namespaced {
+			namespace = "(all)"
+		}
+
+		missing = append(missing, []string{permission.verb, resource, namespace})
+	}
+
+	if len(missing) == 0 {
+		_, err := fmt.Println("All permissions needed by the checks are granted.")
+		return err
+	}
+
+	_, err = fmt.Printf("%d permissions needed by the checks are missing.\n", len(missing))
+	if err != nil {
+		return err
+	}
+
+	err = Table{
+		Header: []string{"Verb", "Resource", "Namespace"},
+		Rows:   missing,
+	}.Fprint(os.Stdout, options.Colored)
+	if err != nil {
+		return err
+	}
+
+	return cli.Exit("", 1)
+}
+
+// PrintClusterRole writes a ClusterRole granting the permissions needed by the selected checks in all namespaces
+// and a Role per namespace granting the permissions needed in single namespaces only, e.g. to exec into the
+// cassandra pods. If a service account ("namespace:name") is given, the roles are bound to it.
+func PrintClusterRole(w io.Writer, options Options, serviceAccount string) error {
+	checks, err := selectChecks(options.Only, options.Skip, options.Config)
+	if err != nil {
+		return err
+	}
+
+	var subject *rbacv1.Subject
+	if serviceAccount != "" {
+		namespace, name, ok := strings.Cut(serviceAccount, ":")
+		if !ok || namespace == "" || name == "" {
+			return fmt.Errorf("service account %q must be formatted as namespace:name", serviceAccount)
+		}
+
+		subject = &rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: name}
+	}
+
+	objects := []any{}
+	for _, role := range roles(requiredPermissions(checks, options.Config, []string{""})) {
+		objects = append(objects, role)
+
+		if subject != nil {
+			objects = append(objects, roleBinding(role, *subject))
+		}
+	}
+
+	for i, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+
+		// the objects are not read from the API server, their empty timestamps are noise
+		out = bytes.Replace(out, []byte("  creationTimestamp: null\n"), nil, 1)
+
+		if i > 0 {
+			out = append([]byte("---\n"), out...)
+		}
+
+		_, err = w.Write(out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// roles returns a ClusterRole with the permissions in all namespaces and of cluster-scoped resources, followed by
+// a Role per namespace with the permissions limited to that namespace.
+func roles(permissions []permission) []metav1.Object {
+	clusterRole := &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: roleName,
+		},
+		Rules: []rbacv1.PolicyRule{},
+	}
+	objects := []metav1.Object{clusterRole}
+	namespaced := map[string]*rbacv1.Role{}
+
+	for _, permission := range permissions {
+		if permission.namespace == "" {
+			clusterRole.Rules = addRule(clusterRole.Rules, permission)
+			continue
+		}
+
+		role, ok := namespaced[permission.namespace]
+		if !ok {
+			role = &rbacv1.Role{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "Role",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      roleName,
+					Namespace: permission.namespace,
+				},
+				Rules: []rbacv1.PolicyRule{},
+			}
+			namespaced[permission.namespace] = role
+			objects = append(objects, role)
+		}
+
+		role.Rules = addRule(role.Rules, permission)
+	}
+
+	return objects
+}
+
+// addRule merges a permission into the rules, one rule per resource and object name.
+func addRule(rules []rbacv1.PolicyRule, permission permission) []rbacv1.PolicyRule {
+	resource := permission.resource.name
+	if permission.subresource != "" {
+		resource += "/" + permission.subresource
+	}
+
+	resourceNames := []string(nil)
+	if permission.name != "" {
+		resourceNames = []string{permission.name}
+	}
+
+	verbs := []string{permission.verb}
+	if permission.verb == "list" && permission.namespace == "" {
+		// the informers of watch and serve need to watch as well, they only cache resources listed in all namespaces
+		verbs = []string{"get", "list", "watch"}
+	}
+
+	i := slices.IndexFunc(rules, func(rule rbacv1.PolicyRule) bool {
+		return rule.APIGroups[0] == permission.resource.group && rule.Resources[0] == resource &&
+			slices.Equal(rule.ResourceNames, resourceNames)
+	})
+	if i < 0 {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{permission.resource.group},
+			Resources:     []string{resource},
+			ResourceNames: resourceNames,
+		})
+		i = len(rules) - 1
+	}
+
+	for _, verb := range verbs {
+		if !slices.Contains(rules[i].Verbs, verb) {
+			rules[i].Verbs = append(rules[i].Verbs, verb)
+		}
+	}
+
+	return rules
+}
+
+// roleBinding binds a ClusterRole or Role to a subject, ClusterRoles are bound cluster-wide.
+func roleBinding(role metav1.Object, subject rbacv1.Subject) any {
+	if _, ok := role.(*rbacv1.ClusterRole); ok {
+		return &rbacv1.ClusterRoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacv1.SchemeGroupVersion.String(),
+				Kind:       "ClusterRoleBinding",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: role.GetName(),
+			},
+			RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: role.GetName()},
+			Subjects: []rbacv1.Subject{subject},
+		}
+	}
+
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "RoleBinding",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      role.GetName(),
+			Namespace: role.GetNamespace(),
+		},
+		RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.GetName()},
+		Subjects: []rbacv1.Subject{subject},
+	}
+}
@@ -0,0 +1,153 @@
+package k8status
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_requiredPermissions(t *testing.T) {
+	config := DefaultConfig()
+	config.RookCeph.Namespace = "storage"
+
+	selected := []check{}
+	for _, check := range checks {
+		if check.name == "nodes" || check.name == "rook-ceph" || check.name == "pods" {
+			selected = append(selected, check)
+		}
+	}
+
+	want := []permission{
+		{verb: "list", resource: resourceNodes},
+		{verb: "list", resource: resourceNamespaces},
+		{verb: "list", resource: resourcePods, namespace: "storage"},
+		{verb: "create", resource: resourcePods, subresource: "exec", namespace: "storage"},
+		{verb: "list", resource: resourcePods, namespace: "team-a"},
+		{verb: "list", resource: resourceReplicaSets, namespace: "team-a"},
+		{verb: "list", resource: resourceJobs, namespace: "team-a"},
+		{verb: "list", resource: resourceDeployments, namespace: "team-a"},
+		{verb: "list", resource: resourceStatefulSets, namespace: "team-a"},
+		{verb: "list", resource: resourceDaemonSets, namespace: "team-a"},
+		{verb: "list", resource: resourceCronJobs, namespace: "team-a"},
+		{verb: "list", resource: resourceEvents, namespace: "team-a"},
+	}
+
+	got := requiredPermissions(selected, config, []string{"team-a"})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requiredPermissions() = %v, want %v", got, want)
+	}
+}
+
+func Test_roles(t *testing.T) {
+	config := DefaultConfig()
+
+	selected := []check{}
+	for _, check := range checks {
+		if check.name == "cassandra" || check.name == "rook-ceph" || check.name == "deployments" {
+			selected = append(selected, check)
+		}
+	}
+
+	want := []metav1.Object{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: "k8status"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"get", "list", "watch"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list", "watch"}},
+				{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"get", "list", "watch"}},
+			},
+		},
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: "k8status", Namespace: "cassandra"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"k8ssandra-superuser"}, Verbs: []string{"get"}},
+				{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+			},
+		},
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: "k8status", Namespace: "rook-ceph"},
+			Rules: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}},
+				{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}},
+			},
+		},
+	}
+
+	got := roles(requiredPermissions(selected, config, []string{""}))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("roles() = %v, want %v", got, want)
+	}
+
+	// secrets and exec must never be granted cluster-wide
+	for _, rule := range got[0].(*rbacv1.ClusterRole).Rules {
+		if slices.Contains(rule.Resources, "secrets") || slices.Contains(rule.Resources, "pods/exec") {
+			t.Errorf("ClusterRole grants %v on %v", rule.Verbs, rule.Resources)
+		}
+	}
+}
+
+func Test_PrintClusterRole(t *testing.T) {
+	tests := []struct {
+		name string
+		only []string
+		want string
+	}{
+		{
+			name: "rook-ceph",
+			only: []string{"rook-ceph"},
+			want: `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: k8status
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+  - watch
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: k8status
+  namespace: rook-ceph
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - list
+- apiGroups:
+  - ""
+  resources:
+  - pods/exec
+  verbs:
+  - create
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			err := PrintClusterRole(buffer, Options{Only: tt.only, Config: DefaultConfig()}, "")
+			if err != nil {
+				t.Fatalf("PrintClusterRole() error = %v", err)
+			}
+
+			if got := buffer.String(); got != tt.want {
+				t.Errorf("PrintClusterRole() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package k8status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	OutputText   = "text"
+	OutputJSON   = "json"
+	OutputYAML   = "yaml"
+	OutputJUnit  = "junit"
+	OutputNagios = "nagios"
+)
+
+var OutputFormats = []string{OutputText, OutputJSON, OutputYAML, OutputJUnit, OutputNagios}
+
+// report is the machine-readable outcome of a single check.
+type report struct {
+	Total     int
+	Healthy   int
+	Ignored   int
+	Warnings  int
+	Unhealthy int
+	Details   Table
+}
+
+type document struct {
+	Time     time.Time       `json:"time"`
+	Severity Severity        `json:"severity"`
+	ExitCode int             `json:"exitCode"`
+	Checks   []checkDocument `json:"checks"`
+}
+
+type checkDocument struct {
+	Name      string   `json:"name"`
+	Total     int      `json:"total"`
+	Healthy   int      `json:"healthy"`
+	Ignored   int      `json:"ignored"`
+	Warnings  int      `json:"warnings"`
+	Unhealthy int      `json:"unhealthy"`
+	Severity  Severity `json:"severity"`
+	ExitCode  int      `json:"exitCode"`
+	Error     string   `json:"error,omitempty"`
+	Details   Table    `json:"details"`
+}
+
+func validateOutput(output string) error {
+	for _, format := range OutputFormats {
+		if output == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown output format %q, expected one of %v", output, OutputFormats)
+}
+
+// Document describes the results, its exit code matches the process exit code of the exit code mode.
+func (results results) Document(now time.Time, exitCodeMode string) document {
+	doc := document{
+		Time:     now,
+		Severity: results.Severity(),
+		ExitCode: results.exitCodeFor(exitCodeMode),
+		Checks:   []checkDocument{},
+	}
+
+	for _, result := range results {
+		doc.Checks = append(doc.Checks, result.Document())
+	}
+
+	return doc
+}
+
+func (result *result) Document() checkDocument {
+	check := checkDocument{
+		Name:      result.name,
+		Total:     result.report.Total,
+		Healthy:   result.report.Healthy,
+		Ignored:   result.report.Ignored,
+		Warnings:  result.report.Warnings,
+		Unhealthy: result.report.Unhealthy,
+		Severity:  result.severity,
+		ExitCode:  result.exitCode,
+		Details:   result.report.Details,
+	}
+
+	if result.err != nil {
+		check.Error = result.err.Error()
+	}
+
+	if check.Details.Header == nil {
+		check.Details.Header = []string{}
+	}
+
+	if check.Details.Rows == nil {
+		check.Details.Rows = [][]string{}
+	}
+
+	return check
+}
+
+func (results results) Print(w io.Writer, output, exitCodeMode string, now time.Time) error {
+	switch output {
+	case OutputJSON:
+		return results.JSON(w, now, exitCodeMode)
+	case OutputYAML:
+		return results.YAML(w, now, exitCodeMode)
+	case OutputJUnit:
+		return results.JUnit(w, now)
+	case OutputNagios:
+		return results.Nagios(w)
+	default:
+		return results.Text(w)
+	}
+}
+
+func (results results) JSON(w io.Writer, now time.Time, exitCodeMode string) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(results.Document(now, exitCodeMode))
+}
+
+func (results results) YAML(w io.Writer, now time.Time, exitCodeMode string) error {
+	out, err := yaml.Marshal(results.Document(now, exitCodeMode))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
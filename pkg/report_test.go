@@ -0,0 +1,106 @@
+package k8status
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_results_JSON(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := results{
+		{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}},
+		{
+			name:     "pods",
+			severity: SeverityCritical,
+			exitCode: exitCodePods,
+			report: report{
+				Total:     2,
+				Healthy:   1,
+				Unhealthy: 1,
+				Details: Table{
+					Header:     []string{"Namespace", "Pod"},
+					Rows:       [][]string{{"shop", "web-1"}},
+					Severities: []Severity{SeverityCritical},
+				},
+			},
+		},
+		{name: "cassandra", severity: SeverityUnknown, exitCode: exitCodeUnknown, err: errors.New("connection refused")},
+	}
+
+	buffer := &bytes.Buffer{}
+	err := results.Print(buffer, OutputJSON, ExitCodeModeSeverity, now)
+	if err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+
+	got := map[string]any{}
+	err = json.Unmarshal(buffer.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("decode %s: %v", buffer.String(), err)
+	}
+
+	want := map[string]any{
+		"time":     "2024-05-01T12:00:00Z",
+		"severity": "critical",
+		"exitCode": float64(exitCodePods),
+		"checks": []any{
+			map[string]any{
+				"name": "nodes", "total": float64(3), "healthy": float64(3), "ignored": float64(0),
+				"warnings": float64(0), "unhealthy": float64(0), "severity": "ok", "exitCode": float64(0),
+				"details": map[string]any{"header": []any{}, "rows": []any{}},
+			},
+			map[string]any{
+				"name": "pods", "total": float64(2), "healthy": float64(1), "ignored": float64(0),
+				"warnings": float64(0), "unhealthy": float64(1), "severity": "critical", "exitCode": float64(exitCodePods),
+				"details": map[string]any{
+					"header":     []any{"Namespace", "Pod"},
+					"rows":       []any{[]any{"shop", "web-1"}},
+					"severities": []any{"critical"},
+				},
+			},
+			map[string]any{
+				"name": "cassandra", "total": float64(0), "healthy": float64(0), "ignored": float64(0),
+				"warnings": float64(0), "unhealthy": float64(0), "severity": "unknown", "exitCode": float64(exitCodeUnknown),
+				"error":   "connection refused",
+				"details": map[string]any{"header": []any{}, "rows": []any{}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON() = %v, want %v", got, want)
+	}
+}
+
+func Test_results_Document_exitCodeMode(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	results := results{
+		{name: "nodes", severity: SeverityWarning},
+		{name: "pods", severity: SeverityCritical, exitCode: exitCodePods},
+	}
+	clusters := []clusterResults{{name: "prod", results: results}}
+
+	tests := []struct {
+		mode string
+		want int
+	}{
+		{mode: ExitCodeModeSeverity, want: exitCodePods},
+		{mode: ExitCodeModeBitmask, want: exitBitWarning | exitBitWorkloads},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			if got := results.Document(now, tt.mode).ExitCode; got != tt.want {
+				t.Errorf("Document().ExitCode = %d, want %d", got, tt.want)
+			}
+
+			doc := clustersDocumentOf(clusters, now, tt.mode)
+			if doc.ExitCode != tt.want || doc.Clusters[0].ExitCode != tt.want {
+				t.Errorf("clustersDocumentOf() exit codes = %d, %d, want %d", doc.ExitCode, doc.Clusters[0].ExitCode, tt.want)
+			}
+		})
+	}
+}
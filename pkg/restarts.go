@@ -0,0 +1,94 @@
+package k8status
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restartSample is the restart count of a pod at the time of an evaluation.
+type restartSample struct {
+	at       time.Time
+	restarts int32
+}
+
+// restartHistory remembers the restart counts of pods across the evaluations of watch and serve.
+// A single evaluation only knows when the last restart of every container happened.
+type restartHistory struct {
+	mutex   sync.Mutex
+	samples map[types.UID][]restartSample
+}
+
+// observe records the restart counts of the pods and returns the restarts of every pod within the window.
+// Pods which are not observed anymore are forgotten.
+func (history *restartHistory) observe(pods []v1.Pod, now time.Time, window time.Duration) map[types.UID]int32 {
+	history.mutex.Lock()
+	defer history.mutex.Unlock()
+
+	samples := map[types.UID][]restartSample{}
+	recent := map[types.UID]int32{}
+
+	for _, pod := range pods {
+		previous := history.samples[pod.UID]
+		recent[pod.UID] = recentRestarts(pod, previous, now, window)
+		samples[pod.UID] = appendSample(previous, restartSample{at: now, restarts: getRestarts(pod)}, now, window)
+	}
+
+	history.samples = samples
+
+	return recent
+}
+
+// appendSample keeps the samples within the window and the latest sample before it as baseline.
+func appendSample(samples []restartSample, sample restartSample, now time.Time, window time.Duration) []restartSample {
+	samples = append(samples, sample)
+	start := now.Add(-window)
+
+	i := 0
+	for i+1 < len(samples) && !samples[i+1].at.After(start) {
+		i++
+	}
+
+	return samples[i:]
+}
+
+// recentRestarts counts the restarts of a pod within the window. Without a sample from before the window,
+// all restarts are attributed to the window only if the pod started within it. Otherwise the restarts since
+// the first sample and the last restart of every container are known.
+func recentRestarts(pod v1.Pod, samples []restartSample, now time.Time, window time.Duration) int32 {
+	current := getRestarts(pod)
+	start := now.Add(-window)
+
+	if pod.Status.StartTime != nil && pod.Status.StartTime.After(start) {
+		return current
+	}
+
+	// the latest sample before the window is the baseline
+	baseline := -1
+	for i, sample := range samples {
+		if !sample.at.After(start) {
+			baseline = i
+		}
+	}
+
+	if baseline >= 0 {
+		return max(current-samples[baseline].restarts, 0)
+	}
+
+	since := int32(0)
+	if len(samples) > 0 {
+		since = current - samples[0].restarts
+	}
+
+	last := int32(0)
+	for _, container := range pod.Status.ContainerStatuses {
+		terminated := container.LastTerminationState.Terminated
+		if terminated != nil && terminated.FinishedAt.After(start) {
+			last++
+		}
+	}
+
+	return max(since, last)
+}
@@ -0,0 +1,95 @@
+package k8status
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_recentRestarts(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+
+	pod := func(started time.Duration, restarts int32, lastRestart time.Duration) v1.Pod {
+		return v1.Pod{Status: v1.PodStatus{
+			StartTime: &metav1.Time{Time: now.Add(-started)},
+			ContainerStatuses: []v1.ContainerStatus{{
+				RestartCount: restarts,
+				LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					FinishedAt: metav1.NewTime(now.Add(-lastRestart)),
+				}},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		pod     v1.Pod
+		samples []restartSample
+		want    int32
+	}{
+		{name: "old pod with a single recent restart", pod: pod(30*24*time.Hour, 3, 5*time.Minute), want: 1},
+		{name: "old pod restarted long ago", pod: pod(30*24*time.Hour, 3, time.Hour), want: 0},
+		{name: "young pod", pod: pod(10*time.Minute, 3, time.Minute), want: 3},
+		{
+			name:    "baseline before the window",
+			pod:     pod(30*24*time.Hour, 7, time.Minute),
+			samples: []restartSample{{at: now.Add(-20 * time.Minute), restarts: 3}, {at: now.Add(-5 * time.Minute), restarts: 5}},
+			want:    4,
+		},
+		{
+			name:    "samples within the window only",
+			pod:     pod(30*24*time.Hour, 7, time.Minute),
+			samples: []restartSample{{at: now.Add(-5 * time.Minute), restarts: 4}},
+			want:    3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recentRestarts(tt.pod, tt.samples, now, window)
+			if got != tt.want {
+				t.Errorf("recentRestarts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_restartHistory_observe(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+
+	pod := func(restarts int32) v1.Pod {
+		return v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{UID: "web-1"},
+			Status: v1.PodStatus{
+				StartTime:         &metav1.Time{Time: start.Add(-24 * time.Hour)},
+				ContainerStatuses: []v1.ContainerStatus{{RestartCount: restarts}},
+			},
+		}
+	}
+
+	history := &restartHistory{}
+	steps := []struct {
+		after    time.Duration
+		restarts int32
+		want     int32
+	}{
+		{after: 0, restarts: 10, want: 0},
+		{after: 10 * time.Minute, restarts: 11, want: 1},
+		{after: 20 * time.Minute, restarts: 13, want: 3},
+		{after: 40 * time.Minute, restarts: 13, want: 0},
+	}
+	for _, step := range steps {
+		got := history.observe([]v1.Pod{pod(step.restarts)}, start.Add(step.after), window)
+		if got["web-1"] != step.want {
+			t.Errorf("observe() after %v = %d, want %d", step.after, got["web-1"], step.want)
+		}
+	}
+
+	history.observe(nil, start.Add(time.Hour), window)
+	if len(history.samples) != 0 {
+		t.Errorf("observe() kept %d pods which are gone", len(history.samples))
+	}
+}
@@ -5,15 +5,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/json"
 )
 
 const (
-	rookCephNamespace = "rook-ceph"
-	rookCephLabel     = "app=rook-ceph-tools"
-	rookCephStatusOk  = "HEALTH_OK"
+	rookCephStatusOk   = "HEALTH_OK"
+	rookCephStatusWarn = "HEALTH_WARN"
 )
 
 type rookCephStatus struct {
@@ -35,8 +35,8 @@ type CephHealth struct {
 	} `json:"checks"`
 }
 
-func NewRookCephStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	exists, err := namespaceExists(ctx, client.clientset, rookCephNamespace)
+func NewRookCephStatus(ctx context.Context, env *environment) (status, error) {
+	exists, err := namespaceExists(ctx, env.client, env.config.RookCeph.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +49,7 @@ func NewRookCephStatus(ctx context.Context, client *KubernetesClient) (status, e
 		return status, nil
 	}
 
-	health, err := getRookCephHealth(ctx, client)
+	health, err := getRookCephHealth(ctx, env.client, env.config.RookCeph)
 	if err != nil {
 		return nil, err
 	}
@@ -59,12 +59,20 @@ func NewRookCephStatus(ctx context.Context, client *KubernetesClient) (status, e
 	return status, nil
 }
 
-func getRookCephHealth(ctx context.Context, client *KubernetesClient) (CephHealth, error) {
-	listOptions := metav1.ListOptions{
-		LabelSelector: rookCephLabel,
+func rookCephPermissions(config Config) []permission {
+	return []permission{
+		{verb: "list", resource: resourcePods, namespace: config.RookCeph.Namespace},
+		{verb: "create", resource: resourcePods, subresource: "exec", namespace: config.RookCeph.Namespace},
+	}
+}
+
+func getRookCephHealth(ctx context.Context, client *KubernetesClient, config RookCephConfig) (CephHealth, error) {
+	selector, err := labels.Parse(config.ToolsSelector)
+	if err != nil {
+		return CephHealth{}, fmt.Errorf("parse rook-ceph-tools label: %v", err)
 	}
 
-	pods, err := listPods(ctx, client.clientset, rookCephNamespace, listOptions)
+	pods, err := listPods(ctx, client, config.Namespace, selector)
 	if err != nil {
 		return CephHealth{}, fmt.Errorf("lookup rook-ceph-tools pod: %v", err)
 	}
@@ -75,8 +83,9 @@ func getRookCephHealth(ctx context.Context, client *KubernetesClient) (CephHealt
 
 	output := &bytes.Buffer{}
 	err = exec(
+		ctx,
 		client,
-		rookCephNamespace,
+		config.Namespace,
 		pods[0].Name,
 		"",
 		"ceph status --format json",
@@ -124,13 +133,79 @@ func (s *rookCephStatus) Details(w io.Writer, colored bool) error {
 func (s *rookCephStatus) ExitCode() int {
 	// @TODO: Exit Code 48 ( status.sh, line 115	)
 
+	if s.Severity() == SeverityCritical {
+		return exitCodeRookCeph
+	}
+
+	return 0
+}
+
+func (s *rookCephStatus) Severity() Severity {
 	if !s.found {
-		return 0
+		return SeverityOK
 	}
 
-	if s.health.Status != rookCephStatusOk {
-		return 47
+	return cephSeverity(s.health.Status)
+}
+
+func cephSeverity(status string) Severity {
+	switch status {
+	case rookCephStatusOk:
+		return SeverityOK
+	case rookCephStatusWarn:
+		return SeverityWarning
+	default:
+		return SeverityCritical
 	}
+}
 
-	return 0
+func (s *rookCephStatus) Report() report {
+	if !s.found {
+		return report{
+			Details: s.toTable(),
+		}
+	}
+
+	healthy := 0
+	if s.health.Status == rookCephStatusOk {
+		healthy = 1
+	}
+
+	warnings := 0
+	if s.Severity() == SeverityWarning {
+		warnings = 1
+	}
+
+	return report{
+		Total:     1,
+		Healthy:   healthy,
+		Warnings:  warnings,
+		Unhealthy: 1 - healthy,
+		Details:   s.toTable(),
+	}
+}
+
+func (s *rookCephStatus) toTable() Table {
+	header := []string{"Check", "Message"}
+
+	names := []string{}
+	for name := range s.health.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := [][]string{}
+	severities := []Severity{}
+	for _, name := range names {
+		check := s.health.Checks[name]
+		row := []string{name, check.Summary.Message}
+		rows = append(rows, row)
+		severities = append(severities, cephSeverity(check.Severity))
+	}
+
+	return Table{
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
+	}
 }
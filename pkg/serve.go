@@ -0,0 +1,277 @@
+package k8status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type server struct {
+	client   *KubernetesClient
+	checks   []check
+	config   Config
+	interval time.Duration
+	// exitCodeMode derives the exit code of the report at /healthz.
+	exitCodeMode string
+
+	mutex    sync.RWMutex
+	results  results
+	updated  time.Time
+	duration time.Duration
+}
+
+type metric struct {
+	name  string
+	help  string
+	value func(result *result) float64
+}
+
+var checkMetrics = []metric{
+	{
+		name:  "k8status_check_total",
+		help:  "Number of objects inspected by the check.",
+		value: func(result *result) float64 { return float64(result.report.Total) },
+	},
+	{
+		name:  "k8status_check_healthy",
+		help:  "Number of healthy objects found by the check.",
+		value: func(result *result) float64 { return float64(result.report.Healthy) },
+	},
+	{
+		name:  "k8status_check_unhealthy",
+		help:  "Number of unhealthy objects found by the check, including ignored ones.",
+		value: func(result *result) float64 { return float64(result.report.Unhealthy) },
+	},
+	{
+		name:  "k8status_check_ignored",
+		help:  "Number of unhealthy objects ignored by the check.",
+		value: func(result *result) float64 { return float64(result.report.Ignored) },
+	},
+	{
+		name:  "k8status_check_warnings",
+		help:  "Number of unhealthy objects reported as warning by the check.",
+		value: func(result *result) float64 { return float64(result.report.Warnings) },
+	},
+	{
+		name:  "k8status_check_exit_code",
+		help:  "Exit code of the check, 0 if the check passed.",
+		value: func(result *result) float64 { return float64(result.exitCode) },
+	},
+	{
+		name:  "k8status_check_severity",
+		help:  "Severity of the check: 0 ok, 1 warning, 2 critical, 3 unknown.",
+		value: func(result *result) float64 { return float64(result.severity) },
+	},
+	{
+		name: "k8status_check_error",
+		help: "1 if the check could not be evaluated, 0 otherwise.",
+		value: func(result *result) float64 {
+			if result.err != nil {
+				return 1
+			}
+			return 0
+		},
+	},
+}
+
+// Serve evaluates all checks whenever the cluster changed, at least every interval, and exposes the latest results as prometheus metrics
+// and as http health endpoints.
+func Serve(ctx context.Context, client *KubernetesClient, options Options, address string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", interval)
+	}
+
+	checks, exitCodeMode, err := options.prepare()
+	if err != nil {
+		return err
+	}
+
+	err = client.StartCache(ctx, checks)
+	if err != nil {
+		return err
+	}
+
+	s := &server{
+		client:   client,
+		checks:   checks,
+		config:   options.Config,
+		interval: interval,
+
+		exitCodeMode: exitCodeMode,
+	}
+
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go s.refresh(ctx)
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Printf("shut down http server: %v", err)
+		}
+	}()
+
+	log.Printf("listening on %s", address)
+
+	err = httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", s.metrics)
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /checks/{name}", s.check)
+
+	return mux
+}
+
+func (s *server) refresh(ctx context.Context) {
+	changed := false
+
+	for {
+		start := time.Now()
+
+		checks := s.checks
+		if changed {
+			checks = changeChecks(s.checks)
+		}
+
+		// only this goroutine writes the results
+		results := runChecks(ctx, s.client, checks, &s.config, false).keep(s.results, s.checks)
+
+		s.mutex.Lock()
+		s.results = results
+		s.updated = start
+		s.duration = time.Since(start)
+		s.mutex.Unlock()
+
+		var ok bool
+		changed, ok = s.client.waitForChange(ctx, start, s.interval, s.config.MinInterval.Duration)
+		if !ok {
+			return
+		}
+	}
+}
+
+func (s *server) metrics(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	err := s.writeMetrics(w)
+	if err != nil {
+		log.Printf("write metrics: %v", err)
+	}
+}
+
+func (s *server) writeMetrics(w io.Writer) error {
+	if s.results == nil {
+		return nil
+	}
+
+	for _, metric := range checkMetrics {
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range s.results {
+			_, err := fmt.Fprintf(w, "%s{check=%q} %g\n", metric.name, result.name, metric.value(result))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w,
+		"# HELP k8status_last_run_timestamp_seconds Unix time the latest evaluation started.\n"+
+			"# TYPE k8status_last_run_timestamp_seconds gauge\n"+
+			"k8status_last_run_timestamp_seconds %d\n"+
+			"# HELP k8status_last_run_duration_seconds Duration of the latest evaluation.\n"+
+			"# TYPE k8status_last_run_duration_seconds gauge\n"+
+			"k8status_last_run_duration_seconds %g\n",
+		s.updated.Unix(),
+		s.duration.Seconds(),
+	)
+
+	return err
+}
+
+func (s *server) healthz(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.results == nil {
+		http.Error(w, "no evaluation finished yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	doc := s.results.Document(s.updated, s.exitCodeMode)
+	writeJSON(w, httpStatus(doc.Severity), doc)
+}
+
+func (s *server) check(w http.ResponseWriter, r *http.Request) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.results == nil {
+		http.Error(w, "no evaluation finished yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	name := r.PathValue("name")
+
+	for _, result := range s.results {
+		if result.name != name {
+			continue
+		}
+
+		writeJSON(w, httpStatus(result.severity), result.Document())
+		return
+	}
+
+	http.Error(w, fmt.Sprintf("check %q not found", name), http.StatusNotFound)
+}
+
+// httpStatus reports warnings as available, only critical and unknown outcomes are unavailable.
+func httpStatus(severity Severity) int {
+	if severity == SeverityCritical || severity == SeverityUnknown {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(body)
+	if err != nil {
+		log.Printf("write http response: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package k8status
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_server_handler(t *testing.T) {
+	evaluated := &server{
+		results: results{
+			{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}},
+			{name: "pods", severity: SeverityWarning, report: report{Total: 2, Healthy: 1, Warnings: 1, Unhealthy: 1}},
+			{name: "volumes", severity: SeverityCritical, exitCode: exitCodeVolumes, report: report{Total: 1, Unhealthy: 1}},
+		},
+		updated: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	healthy := &server{
+		results: results{{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}}},
+	}
+
+	tests := []struct {
+		name     string
+		server   *server
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "healthz critical", server: evaluated, path: "/healthz", wantCode: http.StatusServiceUnavailable, wantBody: `"severity": "critical"`},
+		{name: "healthz ok", server: healthy, path: "/healthz", wantCode: http.StatusOK, wantBody: `"severity": "ok"`},
+		{name: "healthz not evaluated", server: &server{}, path: "/healthz", wantCode: http.StatusServiceUnavailable, wantBody: "no evaluation finished yet"},
+		{name: "check ok", server: evaluated, path: "/checks/nodes", wantCode: http.StatusOK, wantBody: `"name": "nodes"`},
+		{name: "check warning", server: evaluated, path: "/checks/pods", wantCode: http.StatusOK, wantBody: `"severity": "warning"`},
+		{name: "check critical", server: evaluated, path: "/checks/volumes", wantCode: http.StatusServiceUnavailable, wantBody: `"exitCode": 42`},
+		{name: "check not found", server: evaluated, path: "/checks/cassandra", wantCode: http.StatusNotFound, wantBody: `check "cassandra" not found`},
+		{name: "check not evaluated", server: &server{}, path: "/checks/nodes", wantCode: http.StatusServiceUnavailable, wantBody: "no evaluation finished yet"},
+		{
+			name: "metrics unhealthy", server: evaluated, path: "/metrics", wantCode: http.StatusOK,
+			wantBody: "# TYPE k8status_check_unhealthy gauge\n" +
+				"k8status_check_unhealthy{check=\"nodes\"} 0\n" +
+				"k8status_check_unhealthy{check=\"pods\"} 1\n" +
+				"k8status_check_unhealthy{check=\"volumes\"} 1\n",
+		},
+		{
+			name: "metrics exit code", server: evaluated, path: "/metrics", wantCode: http.StatusOK,
+			wantBody: "# TYPE k8status_check_exit_code gauge\n" +
+				"k8status_check_exit_code{check=\"nodes\"} 0\n" +
+				"k8status_check_exit_code{check=\"pods\"} 0\n" +
+				"k8status_check_exit_code{check=\"volumes\"} 42\n",
+		},
+		{
+			name: "metrics last run", server: evaluated, path: "/metrics", wantCode: http.StatusOK,
+			wantBody: "# TYPE k8status_last_run_timestamp_seconds gauge\nk8status_last_run_timestamp_seconds 1714564800\n",
+		},
+		{name: "metrics not evaluated", server: &server{}, path: "/metrics", wantCode: http.StatusOK, wantBody: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			tt.server.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if recorder.Code != tt.wantCode {
+				t.Errorf("GET %s code = %d, want %d", tt.path, recorder.Code, tt.wantCode)
+			}
+			if !strings.Contains(recorder.Body.String(), tt.wantBody) {
+				t.Errorf("GET %s body = %s, want it to contain %s", tt.path, recorder.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
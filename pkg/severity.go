@@ -0,0 +1,85 @@
+package k8status
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Severity rates the outcome of a check or of a single unhealthy object.
+type Severity int
+
+const (
+	SeverityOK Severity = iota
+	SeverityWarning
+	SeverityCritical
+	SeverityUnknown
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityOK:
+		return "OK"
+	case SeverityWarning:
+		return "WARNING"
+	case SeverityCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToLower(s.String()))
+}
+
+// rank orders severities from best to worst. A critical outcome is worse than a check
+// which could not be evaluated, which in turn is worse than a warning.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityOK:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeverityUnknown:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func worstSeverity(severities ...Severity) Severity {
+	worst := SeverityOK
+
+	for _, severity := range severities {
+		if severity.rank() > worst.rank() {
+			worst = severity
+		}
+	}
+
+	return worst
+}
+
+// countedSeverity rates a check counting unhealthy objects, ignored and warning objects are part of unhealthy.
+func countedSeverity(unhealthy int, ignored int, warnings int) Severity {
+	if unhealthy > ignored+warnings {
+		return SeverityCritical
+	}
+
+	if warnings > 0 {
+		return SeverityWarning
+	}
+
+	return SeverityOK
+}
+
+// severity rates an unhealthy object by its impact on the report.
+func (i impact) severity() Severity {
+	switch i {
+	case impactWarning:
+		return SeverityWarning
+	case impactIgnored:
+		return SeverityOK
+	default:
+		return SeverityCritical
+	}
+}
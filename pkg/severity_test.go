@@ -0,0 +1,55 @@
+package k8status
+
+import (
+	"testing"
+)
+
+func Test_results_ExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		results results
+		want    int
+	}{
+		{
+			name: "all checks ok",
+			results: results{
+				{name: "nodes", severity: SeverityOK},
+				{name: "pods", severity: SeverityOK},
+			},
+			want: 0,
+		},
+		{
+			name: "warnings only",
+			results: results{
+				{name: "nodes", severity: SeverityOK},
+				{name: "pods", severity: SeverityWarning},
+			},
+			want: exitCodeWarning,
+		},
+		{
+			name: "check errors are worse than warnings",
+			results: results{
+				{name: "nodes", severity: SeverityWarning},
+				{name: "pods", severity: SeverityUnknown, exitCode: exitCodeUnknown},
+			},
+			want: exitCodeUnknown,
+		},
+		{
+			name: "first critical check wins",
+			results: results{
+				{name: "nodes", severity: SeverityUnknown, exitCode: exitCodeUnknown},
+				{name: "deployments", severity: SeverityCritical, exitCode: exitCodeDeployments},
+				{name: "cronjobs", severity: SeverityCritical, exitCode: exitCodeCronjobs},
+			},
+			want: exitCodeDeployments,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.results.ExitCode()
+			if got != tt.want {
+				t.Errorf("results.ExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
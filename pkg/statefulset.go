@@ -6,35 +6,42 @@ import (
 	"io"
 
 	appsv1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type statefulsetsStatus struct {
+	policy       *policy
+	events       eventIndex
 	total        int
 	ignored      int
+	warnings     int
 	healthy      int
 	statefulsets []appsv1.StatefulSet
 	unhealthy    int
 }
 
-func NewStatefulsetsStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	statefulsetsList, err := client.clientset.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+func NewStatefulsetsStatus(ctx context.Context, env *environment) (status, error) {
+	statefulsets, err := listInScope(env, func(namespace string) ([]appsv1.StatefulSet, error) {
+		return listStatefulSets(ctx, env.client, namespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	statefulsets := statefulsetsList.Items
-
 	status := &statefulsetsStatus{
+		policy:       env.policy,
 		statefulsets: []appsv1.StatefulSet{},
 	}
 	status.add(statefulsets)
 
+	if status.unhealthy > 0 {
+		status.events = env.warningEvents(ctx)
+	}
+
 	return status, nil
 }
 
 func (s *statefulsetsStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d statefulsets are healthy.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d statefulsets are healthy.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *statefulsetsStatus) Details(w io.Writer, colored bool) error {
@@ -42,17 +49,33 @@ func (s *statefulsetsStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *statefulsetsStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 50
+	if s.Severity() == SeverityCritical {
+		return exitCodeStatefulsets
 	}
 
 	return 0
 }
 
+func (s *statefulsetsStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *statefulsetsStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *statefulsetsStatus) toTable() Table {
-	header := []string{"Namespace", "Statefulset", "Replicas", "Ready", "Current", "Updated"}
+	header := []string{"Namespace", "Statefulset", "Replicas", "Ready", "Current", "Updated", "Last Event"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.statefulsets {
 		row := []string{
 			item.Namespace,
@@ -61,13 +84,16 @@ func (s *statefulsetsStatus) toTable() Table {
 			fmt.Sprintf("%d", item.Status.ReadyReplicas),
 			fmt.Sprintf("%d", item.Status.CurrentReplicas),
 			fmt.Sprintf("%d", item.Status.UpdatedReplicas),
+			s.events.last(item.UID),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, item.Namespace).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -80,8 +106,11 @@ func (s *statefulsetsStatus) add(statefulsets []appsv1.StatefulSet) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, item.Namespace) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.statefulsets = append(s.statefulsets, item)
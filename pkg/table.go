@@ -7,8 +7,10 @@ import (
 )
 
 type Table struct {
-	Header []string
-	Rows   [][]string
+	Header []string   `json:"header"`
+	Rows   [][]string `json:"rows"`
+	// Severities rates the rows, it is either empty or as long as Rows.
+	Severities []Severity `json:"severities,omitempty"`
 }
 
 func (t Table) Fprint(w io.Writer, colored bool) error {
@@ -16,13 +18,25 @@ func (t Table) Fprint(w io.Writer, colored bool) error {
 		return nil
 	}
 
+	header := t.Header
+	rows := t.Rows
+
+	if len(t.Severities) == len(t.Rows) {
+		header = append([]string{"Severity"}, t.Header...)
+		rows = [][]string{}
+
+		for i, row := range t.Rows {
+			rows = append(rows, append([]string{rowSeverity(t.Severities[i])}, row...))
+		}
+	}
+
 	table := tablewriter.NewWriter(w)
-	table.SetHeader(t.Header)
+	table.SetHeader(header)
 
 	if colored {
 		titleColor := tablewriter.Colors{tablewriter.Bold, tablewriter.FgYellowColor}
 		headerColors := []tablewriter.Colors{}
-		for i := 0; i < len(t.Header); i++ {
+		for i := 0; i < len(header); i++ {
 			headerColors = append(headerColors, titleColor)
 		}
 		table.SetHeaderColor(headerColors...)
@@ -37,8 +51,18 @@ func (t Table) Fprint(w io.Writer, colored bool) error {
 		return err
 	}
 
-	table.AppendBulk(t.Rows)
+	table.AppendBulk(rows)
 	table.Render()
 
 	return nil
 }
+
+// rowSeverity labels the severity of a detail row. Detail rows list unhealthy objects,
+// an unhealthy object without impact on the report is ignored.
+func rowSeverity(severity Severity) string {
+	if severity == SeverityOK {
+		return "IGNORED"
+	}
+
+	return severity.String()
+}
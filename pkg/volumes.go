@@ -3,28 +3,34 @@ package k8status
 import (
 	"context"
 	"io"
+	"slices"
 
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type volumesStatus struct {
+	policy    *policy
 	total     int
 	ignored   int
+	warnings  int
 	healthy   int
 	volumes   []v1.PersistentVolume
 	unhealthy int
 }
 
-func NewVolumesStatus(ctx context.Context, client *KubernetesClient) (status, error) {
-	volumesList, err := client.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+func NewVolumesStatus(ctx context.Context, env *environment) (status, error) {
+	volumes, err := listPersistentVolumes(ctx, env.client)
 	if err != nil {
 		return nil, err
 	}
 
-	volumes := volumesList.Items
+	// in namespace scope only the volumes bound to claims of the namespace are evaluated
+	volumes = slices.DeleteFunc(volumes, func(volume v1.PersistentVolume) bool {
+		return !env.inScope(volumeNamespace(volume))
+	})
 
 	status := &volumesStatus{
+		policy:  env.policy,
 		volumes: []v1.PersistentVolume{},
 	}
 	status.add(volumes)
@@ -33,7 +39,7 @@ func NewVolumesStatus(ctx context.Context, client *KubernetesClient) (status, er
 }
 
 func (s *volumesStatus) Summary(w io.Writer) error {
-	return printSummaryWithIgnored(w, "%d of %d volumes are bound or available.\n", s.ignored, s.healthy, s.total)
+	return printSummary(w, "%d of %d volumes are bound or available.\n", s.ignored, s.warnings, s.healthy, s.total)
 }
 
 func (s *volumesStatus) Details(w io.Writer, colored bool) error {
@@ -41,29 +47,47 @@ func (s *volumesStatus) Details(w io.Writer, colored bool) error {
 }
 
 func (s *volumesStatus) ExitCode() int {
-	if s.unhealthy > s.ignored {
-		return 42
+	if s.Severity() == SeverityCritical {
+		return exitCodeVolumes
 	}
 
 	return 0
 }
 
+func (s *volumesStatus) Severity() Severity {
+	return countedSeverity(s.unhealthy, s.ignored, s.warnings)
+}
+
+func (s *volumesStatus) Report() report {
+	return report{
+		Total:     s.total,
+		Healthy:   s.healthy,
+		Ignored:   s.ignored,
+		Warnings:  s.warnings,
+		Unhealthy: s.unhealthy,
+		Details:   s.toTable(),
+	}
+}
+
 func (s *volumesStatus) toTable() Table {
 	header := []string{"Namespace", "Volume", "Phase"}
 
 	rows := [][]string{}
+	severities := []Severity{}
 	for _, item := range s.volumes {
 		row := []string{
-			item.Namespace,
+			volumeNamespace(item),
 			item.Name,
 			string(item.Status.Phase),
 		}
 		rows = append(rows, row)
+		severities = append(severities, s.policy.impact(&item, volumeNamespace(item)).severity())
 	}
 
 	return Table{
-		Header: header,
-		Rows:   rows,
+		Header:     header,
+		Rows:       rows,
+		Severities: severities,
 	}
 }
 
@@ -76,8 +100,11 @@ func (s *volumesStatus) add(pvcs []v1.PersistentVolume) {
 			continue
 		}
 
-		if isCiOrLabNamespace(item.Namespace) {
+		switch s.policy.impact(&item, volumeNamespace(item)) {
+		case impactIgnored:
 			s.ignored++
+		case impactWarning:
+			s.warnings++
 		}
 
 		s.volumes = append(s.volumes, item)
@@ -88,3 +115,13 @@ func (s *volumesStatus) add(pvcs []v1.PersistentVolume) {
 func volumeIsHealthy(item v1.PersistentVolume) bool {
 	return item.Status.Phase == v1.VolumeBound || item.Status.Phase == v1.VolumeAvailable
 }
+
+// volumeNamespace returns the namespace of the claim a volume is bound to.
+// Persistent volumes are cluster scoped and have no namespace of their own.
+func volumeNamespace(item v1.PersistentVolume) string {
+	if item.Spec.ClaimRef == nil {
+		return ""
+	}
+
+	return item.Spec.ClaimRef.Namespace
+}
@@ -0,0 +1,153 @@
+package k8status
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	clearScreen    = "\033[H\033[2J"
+	highlightStart = "\033[1;7m"
+	highlightEnd   = "\033[0m"
+)
+
+// Watch evaluates all checks whenever the cluster changed, at least every interval, and redraws the report in place.
+// Checks whose counts changed since the previous evaluation are highlighted.
+func Watch(ctx context.Context, client *KubernetesClient, options Options, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %v", interval)
+	}
+
+	checks, err := selectChecks(options.Only, options.Skip, options.Config)
+	if err != nil {
+		return err
+	}
+
+	err = client.StartCache(ctx, checks)
+	if err != nil {
+		return err
+	}
+
+	var previous results
+	changed := false
+
+	for {
+		now := time.Now()
+
+		evaluated := checks
+		if changed {
+			evaluated = changeChecks(checks)
+		}
+
+		results := runChecks(ctx, client, evaluated, &options.Config, options.Colored).keep(previous, checks)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		screen := &bytes.Buffer{}
+		_, err = fmt.Fprintf(screen, "%sEvery %v, on changes at most every %v: k8status\t%s\n",
+			clearScreen, interval, options.Config.MinInterval.Duration, now.Format("2006-01-02 15:04:05"))
+		if err != nil {
+			return err
+		}
+
+		err = results.Errors(screen)
+		if err != nil {
+			return err
+		}
+
+		err = results.HighlightedSummaries(screen, previous, options.Colored)
+		if err != nil {
+			return err
+		}
+
+		err = results.Details(screen)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(os.Stdout, screen)
+		if err != nil {
+			return err
+		}
+
+		previous = results
+
+		var ok bool
+		changed, ok = client.waitForChange(ctx, now, interval, options.Config.MinInterval.Duration)
+		if !ok {
+			return nil
+		}
+	}
+}
+
+func (results results) HighlightedSummaries(w io.Writer, previous results, colored bool) error {
+	for _, result := range results {
+		if result.summary == nil {
+			continue
+		}
+
+		changed := previous != nil && result.changedSince(previous.find(result.name))
+
+		for i, line := range strings.SplitAfter(result.summary.String(), "\n") {
+			if line == "" {
+				continue
+			}
+
+			line = summaryPrefix(result.severity, i) + line
+
+			err := writeSummaryLine(w, line, changed, colored)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeSummaryLine(w io.Writer, line string, changed bool, colored bool) error {
+	if !changed {
+		_, err := io.WriteString(w, line)
+		return err
+	}
+
+	if !colored {
+		_, err := io.WriteString(w, "* "+line)
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "%s%s%s\n", highlightStart, strings.TrimSuffix(line, "\n"), highlightEnd)
+	return err
+}
+
+func (results results) find(name string) *result {
+	for _, result := range results {
+		if result.name == name {
+			return result
+		}
+	}
+
+	return nil
+}
+
+func (result *result) changedSince(previous *result) bool {
+	if previous == nil {
+		return true
+	}
+
+	return result.report.Total != previous.report.Total ||
+		result.report.Healthy != previous.report.Healthy ||
+		result.report.Ignored != previous.report.Ignored ||
+		result.report.Warnings != previous.report.Warnings ||
+		result.report.Unhealthy != previous.report.Unhealthy ||
+		result.exitCode != previous.exitCode ||
+		result.severity != previous.severity ||
+		(result.err == nil) != (previous.err == nil)
+}
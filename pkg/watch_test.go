@@ -0,0 +1,77 @@
+package k8status
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func Test_results_HighlightedSummaries(t *testing.T) {
+	previous := results{
+		{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}},
+		{name: "pods", severity: SeverityOK, report: report{Total: 10, Healthy: 10}},
+		{name: "volumes", severity: SeverityOK, report: report{Total: 2, Healthy: 2}},
+	}
+
+	current := func() results {
+		return results{
+			{name: "nodes", severity: SeverityOK, report: report{Total: 3, Healthy: 3}, summary: bytes.NewBufferString("3 of 3 nodes are up and healthy.\n")},
+			{
+				name:     "pods",
+				severity: SeverityCritical,
+				report:   report{Total: 10, Healthy: 9, Unhealthy: 1},
+				summary:  bytes.NewBufferString("9 of 10 pods are healthy.\n- 1 CrashLoopBackOff\n"),
+			},
+			{name: "volumes", severity: SeverityUnknown, err: errors.New("timed out"), summary: bytes.NewBufferString("timed out\n")},
+			{name: "jobs", severity: SeverityOK, report: report{Total: 1, Healthy: 1}, summary: bytes.NewBufferString("1 of 1 jobs are healthy.\n")},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		previous results
+		colored  bool
+		want     string
+	}{
+		{
+			name: "first evaluation",
+			want: "[OK]       3 of 3 nodes are up and healthy.\n" +
+				"[CRITICAL] 9 of 10 pods are healthy.\n" +
+				"           - 1 CrashLoopBackOff\n" +
+				"[UNKNOWN]  timed out\n" +
+				"[OK]       1 of 1 jobs are healthy.\n",
+		},
+		{
+			name:     "changed",
+			previous: previous,
+			want: "[OK]       3 of 3 nodes are up and healthy.\n" +
+				"* [CRITICAL] 9 of 10 pods are healthy.\n" +
+				"*            - 1 CrashLoopBackOff\n" +
+				"* [UNKNOWN]  timed out\n" +
+				"* [OK]       1 of 1 jobs are healthy.\n",
+		},
+		{
+			name:     "changed colored",
+			previous: previous[:2],
+			colored:  true,
+			want: "[OK]       3 of 3 nodes are up and healthy.\n" +
+				highlightStart + "[CRITICAL] 9 of 10 pods are healthy." + highlightEnd + "\n" +
+				highlightStart + "           - 1 CrashLoopBackOff" + highlightEnd + "\n" +
+				highlightStart + "[UNKNOWN]  timed out" + highlightEnd + "\n" +
+				highlightStart + "[OK]       1 of 1 jobs are healthy." + highlightEnd + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := current().HighlightedSummaries(w, tt.previous, tt.colored)
+			if err != nil {
+				t.Fatalf("HighlightedSummaries() error = %v", err)
+			}
+
+			if w.String() != tt.want {
+				t.Errorf("HighlightedSummaries() =\n%q\nwant\n%q", w.String(), tt.want)
+			}
+		})
+	}
+}